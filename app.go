@@ -5,26 +5,49 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/alvintzz/alert-thread/internal/handler"
+	"github.com/alvintzz/alert-thread/internal/janitor"
+	"github.com/alvintzz/alert-thread/internal/queue"
+	"github.com/alvintzz/alert-thread/internal/repository/bridge"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/composite"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/discord"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/email"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/retry"
 	"github.com/alvintzz/alert-thread/internal/repository/notification/slack"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/teams"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/webhook"
+	"github.com/alvintzz/alert-thread/internal/repository/storage/boltdb"
 	"github.com/alvintzz/alert-thread/internal/repository/storage/gmap"
+	"github.com/alvintzz/alert-thread/internal/repository/storage/redis"
 	"github.com/alvintzz/alert-thread/internal/usecase"
 )
 
 // Config is main configuraton for slack-alert service
 type Config struct {
-	Server Server `json:"server"`
-	Log    Log    `json:"log"`
-	Slack  Slack  `json:"slack"`
+	Server       Server                  `json:"server"`
+	Log          Log                     `json:"log"`
+	Slack        Slack                   `json:"slack"`
+	Notification Notification            `json:"notification"`
+	Storage      Storage                 `json:"storage"`
+	Queue        Queue                   `json:"queue"`
+	Webhook      Webhook                 `json:"webhook"`
+	Routing      usecase.RoutingConfig   `json:"routing"`
+	Templates    usecase.TemplatesConfig `json:"templates"`
+	Bridge       Bridge                  `json:"bridge"`
+	Incident     Incident                `json:"incident"`
+	Janitor      Janitor                 `json:"janitor"`
 }
 
 // Server defines server config for http server
@@ -43,6 +66,155 @@ type Log struct {
 // Slack defines slack configuration
 type Slack struct {
 	Token string `json:"token"`
+	Retry Retry  `json:"retry"`
+
+	// SigningSecret enables incident action buttons and slash commands, and verifies the request sent by Slack
+	SigningSecret string `json:"signing_secret"`
+}
+
+// Notification defines which notification channel is used to notify incidents. Type is either slack, teams, discord, email, webhook or composite.
+// Composite delivers channel written as <destination>:<channel> to each of the Destinations, the first one receives channel without destination.
+// Retry replaces the retry configuration inside slack section when it is set
+type Notification struct {
+	Type         string   `json:"type"`
+	Destinations []string `json:"destinations"`
+	Retry        Retry    `json:"retry"`
+	Teams        Teams    `json:"teams"`
+	Discord      Discord  `json:"discord"`
+	Email        Email    `json:"email"`
+	Webhook      Outgoing `json:"webhook"`
+}
+
+// Teams defines Microsoft Teams configuration. Mode is either webhook or graph and Timeout is in seconds
+type Teams struct {
+	Mode         string            `json:"mode"`
+	Webhooks     map[string]string `json:"webhooks"`
+	TenantID     string            `json:"tenant_id"`
+	ClientID     string            `json:"client_id"`
+	ClientSecret string            `json:"client_secret"`
+	RefreshToken string            `json:"refresh_token"`
+	GraphURL     string            `json:"graph_url"`
+	LoginURL     string            `json:"login_url"`
+	Timeout      time.Duration     `json:"timeout"`
+}
+
+// Discord defines Discord bot configuration. Timeout is in seconds
+type Discord struct {
+	Token          string        `json:"token"`
+	Forums         []string      `json:"forums"`
+	GuildID        string        `json:"guild_id"`
+	ArchiveMinutes int           `json:"archive_minutes"`
+	APIURL         string        `json:"api_url"`
+	Timeout        time.Duration `json:"timeout"`
+}
+
+// Email defines SMTP server and recipients of every channel. Timeout is in seconds
+type Email struct {
+	Host       string              `json:"host"`
+	Port       int                 `json:"port"`
+	TLS        bool                `json:"tls"`
+	Username   string              `json:"username"`
+	Password   string              `json:"password"`
+	From       string              `json:"from"`
+	Recipients map[string][]string `json:"recipients"`
+	Timeout    time.Duration       `json:"timeout"`
+}
+
+// Outgoing defines receivers of the outgoing webhook notification. Timeout is in seconds
+type Outgoing struct {
+	URL             string            `json:"url"`
+	ParentURL       string            `json:"parent_url"`
+	ReplyURL        string            `json:"reply_url"`
+	UpdateURL       string            `json:"update_url"`
+	Headers         map[string]string `json:"headers"`
+	Secret          string            `json:"secret"`
+	SignatureHeader string            `json:"signature_header"`
+	Timeout         time.Duration     `json:"timeout"`
+}
+
+// Retry defines how failed notification is retried. BaseDelay and MaxDelay are in seconds
+type Retry struct {
+	MaxAttempts int           `json:"max_attempts"`
+	BaseDelay   time.Duration `json:"base_delay"`
+	MaxDelay    time.Duration `json:"max_delay"`
+}
+
+// Storage defines where the incidents are saved. Type is either memory, bolt or redis
+type Storage struct {
+	Type  string `json:"type"`
+	Path  string `json:"path"`
+	Redis Redis  `json:"redis"`
+}
+
+// Redis defines redis storage configuration. TTL and LockTTL are in seconds
+type Redis struct {
+	Address  string        `json:"address"`
+	Password string        `json:"password"`
+	Database int           `json:"database"`
+	Prefix   string        `json:"prefix"`
+	TTL      time.Duration `json:"ttl"`
+	LockTTL  time.Duration `json:"lock_ttl"`
+}
+
+// Queue defines the job queue processing incoming notifications. ShutdownTimeout is in seconds and default to 30 seconds
+type Queue struct {
+	Workers         int           `json:"workers"`
+	Depth           int           `json:"depth"`
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+}
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// Incident defines how notifications of an incident are processed
+type Incident struct {
+	DedupWindow  time.Duration `json:"dedup_window"`
+	GracePeriod  time.Duration `json:"grace_period"`
+	ReopenWindow time.Duration `json:"reopen_window"`
+}
+
+// Bridge defines the conference bridge linked in every incident. Type is either meet, zoom, jitsi or none
+type Bridge struct {
+	Type  string `json:"type"`
+	Meet  Meet   `json:"meet"`
+	Zoom  Zoom   `json:"zoom"`
+	Jitsi Jitsi  `json:"jitsi"`
+}
+
+// Meet defines Google Meet bridge configuration. Name is shown beside the link
+type Meet struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"`
+}
+
+// Zoom defines Zoom bridge configuration. Rooms is Zoom room link of each channel
+type Zoom struct {
+	Rooms   map[string]string `json:"rooms"`
+	Default string            `json:"default"`
+}
+
+// Jitsi defines Jitsi bridge configuration
+type Jitsi struct {
+	Server     string `json:"server"`
+	RoomPrefix string `json:"room_prefix"`
+}
+
+// Janitor defines how often and which stale incidents are removed from storage
+type Janitor struct {
+	Interval       time.Duration `json:"interval"`
+	StaleTTL       time.Duration `json:"stale_ttl"`
+	AutoCloseReply bool          `json:"auto_close_reply"`
+}
+
+// Webhook defines vendor specific configuration of incoming webhooks
+type Webhook struct {
+	Alertmanager Alertmanager                     `json:"alertmanager"`
+	Generic      map[string]handler.GenericConfig `json:"generic"`
+	Auth         map[string]handler.AuthConfig    `json:"auth"`
+}
+
+// Alertmanager defines alertmanager webhook configuration
+type Alertmanager struct {
+	GroupBy []string `json:"group_by"`
 }
 
 func main() {
@@ -65,40 +237,139 @@ func main() {
 		log.Fatal("Failed to initialize Logger", err)
 	}
 
-	incidentStorage, err := gmap.NewStorage()
+	incidentStorage, err := initStorage(config.Storage)
 	if err != nil {
 		log.Fatal("Failed to initialize storage because", err)
 	}
 
-	notifChannel, err := slack.NewNotification(config.Slack.Token)
+	notifChannel, err := initNotification(config)
 	if err != nil {
 		log.Fatal("Failed to initialize notification because", err)
 	}
 
-	flow := usecase.New(incidentStorage, notifChannel)
+	channelRouter, err := usecase.NewRouter(config.Routing)
+	if err != nil {
+		log.Fatal("Failed to initialize channel routing because", err)
+	}
+
+	messageTemplates, err := usecase.NewTemplates(config.Templates)
+	if err != nil {
+		log.Fatal("Failed to initialize message templates because", err)
+	}
+
+	conferenceBridge, err := initBridge(config.Bridge)
+	if err != nil {
+		log.Fatal("Failed to initialize conference bridge because", err)
+	}
 
-	handlers := handler.New(flow)
+	flow := usecase.New(incidentStorage, notifChannel, usecase.Options{
+		Router:         channelRouter,
+		Templates:      messageTemplates,
+		Bridge:         conferenceBridge,
+		DedupWindow:    config.Incident.DedupWindow * time.Second,
+		GracePeriod:    config.Incident.GracePeriod * time.Second,
+		ReopenWindow:   config.Incident.ReopenWindow * time.Second,
+		StaleTTL:       config.Janitor.StaleTTL * time.Second,
+		AutoCloseReply: config.Janitor.AutoCloseReply,
+	})
+
+	// Periodically remove stale incidents and archive closed ones so the storage does not grow forever
+	var incidentJanitor *janitor.Janitor
+	if config.Janitor.Interval > 0 {
+		incidentJanitor = janitor.New(config.Janitor.Interval*time.Second, flow.ExpireIncidents)
+	}
+
+	genericMappings := map[string]*handler.GenericMapping{}
+	for name, genericConfig := range config.Webhook.Generic {
+		genericMappings[name], err = handler.NewGenericMapping(name, genericConfig)
+		if err != nil {
+			log.Fatal("Failed to initialize generic webhook because", err)
+		}
+	}
+
+	jobQueue := queue.New(config.Queue.Workers, config.Queue.Depth)
+
+	handlers := handler.New(flow, jobQueue, handler.Options{
+		AlertmanagerGroupBy: config.Webhook.Alertmanager.GroupBy,
+		Generic:             genericMappings,
+	})
+
+	// Authentication of each webhook group. Group without config accept every request
+	authMiddlewares := map[string]func(http.Handler) http.Handler{}
+	for _, group := range []string{"datadog", "grafana", "newrelic", "alertmanager", "generic", "admin"} {
+		authMiddlewares[group], err = handler.NewAuthMiddleware(group, config.Webhook.Auth[group])
+		if err != nil {
+			log.Fatal("Failed to initialize webhook authentication because", err)
+		}
+	}
 
 	router := chi.NewRouter()
 	router.Get("/ping", ping)
 
 	// Collection of datadog webhooks
 	datadogWebhook := router.Group(nil)
+	datadogWebhook.Use(authMiddlewares["datadog"])
 	datadogWebhook.Route("/webhook/datadog", func(r chi.Router) {
 		r.Post("/reply-in-thread", handlers.DdogReplyInThread)
 	})
 
+	// Collection of grafana webhooks
 	grafanaWebhook := router.Group(nil)
+	grafanaWebhook.Use(authMiddlewares["grafana"])
 	grafanaWebhook.Route("/webhook/grafana", func(r chi.Router) {
-		//r.Post("/reply-in-thread", hOncall.GrafanaReplyInThread)
+		r.Post("/reply-in-thread", handlers.GrafanaReplyInThread)
 	})
 
-	// Collection of datadog webhooks
+	// Collection of newrelic webhooks
 	newrelicWebhook := router.Group(nil)
+	newrelicWebhook.Use(authMiddlewares["newrelic"])
 	newrelicWebhook.Route("/webhook/newrelic", func(r chi.Router) {
-		//r.Post("/reply-in-thread", hOncall.NewRelicReplyInThread)
+		r.Post("/reply-in-thread", handlers.NewRelicReplyInThread)
 	})
 
+	// Collection of alertmanager webhooks
+	alertmanagerWebhook := router.Group(nil)
+	alertmanagerWebhook.Use(authMiddlewares["alertmanager"])
+	alertmanagerWebhook.Route("/webhook/alertmanager", func(r chi.Router) {
+		r.Post("/reply-in-thread", handlers.AlertmanagerReplyInThread)
+	})
+
+	// Collection of generic webhooks
+	genericWebhook := router.Group(nil)
+	genericWebhook.Use(authMiddlewares["generic"])
+	genericWebhook.Route("/webhook/generic/{name}", func(r chi.Router) {
+		r.Post("/reply-in-thread", handlers.GenericReplyInThread)
+	})
+
+	// Collection of admin endpoints to manage failed notifications. They expose vendor payloads so they are only served when the admin authentication is configured
+	if config.Webhook.Auth["admin"].Configured() {
+		adminEndpoint := router.Group(nil)
+		adminEndpoint.Use(authMiddlewares["admin"])
+		adminEndpoint.Route("/admin/failed-events", func(r chi.Router) {
+			r.Get("/", handlers.ListFailedEvents)
+			r.Get("/{id}", handlers.GetFailedEvent)
+			r.Post("/{id}/replay", handlers.ReplayFailedEvent)
+			r.Delete("/{id}", handlers.DiscardFailedEvent)
+		})
+	} else {
+		log.Warn("Admin endpoints are disabled because webhook.auth.admin is not configured")
+	}
+
+	// Collection of slack interactivity endpoints. They are only served when the signing secret is configured
+	if config.Slack.SigningSecret != "" {
+		slackVerifier, err := handler.NewSlackVerifier(config.Slack.SigningSecret)
+		if err != nil {
+			log.Fatal("Failed to initialize slack verifier because", err)
+		}
+
+		slackEndpoint := router.Group(nil)
+		slackEndpoint.Use(slackVerifier)
+		slackEndpoint.Route("/slack", func(r chi.Router) {
+			r.Post("/interactions", handlers.SlackInteractions)
+			r.Post("/commands", handlers.SlackCommands)
+		})
+	}
+
 	srv := http.Server{
 		Addr:         config.Server.Port,
 		ReadTimeout:  config.Server.ReadTimeout * time.Second,
@@ -106,8 +377,8 @@ func main() {
 		Handler:      router,
 	}
 
-	stop := make(chan os.Signal)
-	signal.Notify(stop, os.Interrupt)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		log.Info("Server is up and running. Ready to receive request at", config.Server.Port)
@@ -126,6 +397,37 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal(err)
 	}
+
+	shutdownTimeout := config.Queue.ShutdownTimeout * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	// Stop the janitor first so only the queued notifications are still using the storage
+	if incidentJanitor != nil {
+		janitorCtx, janitorCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer janitorCancel()
+
+		if err := incidentJanitor.Shutdown(janitorCtx); err != nil {
+			log.Error("Failed to stop janitor because", err)
+		}
+	}
+
+	// Wait for every queued notification to be sent before exit
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer drainCancel()
+
+	log.Printf("Draining notification queue...")
+	if err := jobQueue.Shutdown(drainCtx); err != nil {
+		// Cancelled notifications may still be writing their incident, so the storage is left to be released by the exit
+		log.Error("Failed to drain notification queue, storage is not closed because", err)
+		return
+	}
+	if closer, ok := incidentStorage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Error("Failed to close storage because", err)
+		}
+	}
 	log.Printf("Successfully shut down")
 }
 
@@ -156,6 +458,148 @@ func initLog(level, format string) error {
 	return nil
 }
 
+func initStorage(config Storage) (usecase.Storage, error) {
+	switch config.Type {
+	case "", "memory":
+		return gmap.NewStorage()
+	case "bolt":
+		return boltdb.NewStorage(config.Path)
+	case "redis":
+		return redis.NewStorage(redis.Options{
+			Address:  config.Redis.Address,
+			Password: config.Redis.Password,
+			Database: config.Redis.Database,
+			Prefix:   config.Redis.Prefix,
+			TTL:      config.Redis.TTL * time.Second,
+			LockTTL:  config.Redis.LockTTL * time.Second,
+		})
+	}
+
+	return nil, fmt.Errorf("Unknown storage type %s", config.Type)
+}
+
+func initNotification(config *Config) (usecase.Notification, error) {
+	if config.Notification.Type != "composite" {
+		return initRetry(config, config.Notification.Type)
+	}
+	if len(config.Notification.Destinations) == 0 {
+		return nil, fmt.Errorf("Destinations of composite notification are required")
+	}
+
+	destinations := map[string]composite.Notification{}
+	for _, destination := range config.Notification.Destinations {
+		notif, err := initRetry(config, destination)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to initialize %s because %s", destination, err)
+		}
+		destinations[destination] = notif
+	}
+
+	return composite.NewNotification(destinations, config.Notification.Destinations[0])
+}
+
+// initRetry will wrap the notification channel of the type with retry so each destination is retried with its own rate limit handling
+func initRetry(config *Config, notifType string) (retry.Notification, error) {
+	notif, options, err := initChannel(config, notifType)
+	if err != nil {
+		return nil, err
+	}
+
+	retryConfig := config.Notification.Retry
+	if retryConfig == (Retry{}) {
+		retryConfig = config.Slack.Retry
+	}
+
+	options.MaxAttempts = retryConfig.MaxAttempts
+	options.BaseDelay = retryConfig.BaseDelay * time.Second
+	options.MaxDelay = retryConfig.MaxDelay * time.Second
+	return retry.NewNotification(notif, options)
+}
+
+// initChannel will return the notification channel of the type with its classification of errors to be retried
+func initChannel(config *Config, notifType string) (retry.Notification, retry.Options, error) {
+	switch notifType {
+	case "", "slack":
+		notif, err := slack.NewNotification(config.Slack.Token, slack.Options{
+			Actions: config.Slack.SigningSecret != "",
+		})
+		return notif, retry.Options{RetryAfter: slack.RetryAfter, Retryable: slack.Retryable}, err
+	case "teams":
+		notif, err := teams.NewNotification(teams.Options{
+			Mode:         config.Notification.Teams.Mode,
+			Webhooks:     config.Notification.Teams.Webhooks,
+			TenantID:     config.Notification.Teams.TenantID,
+			ClientID:     config.Notification.Teams.ClientID,
+			ClientSecret: config.Notification.Teams.ClientSecret,
+			RefreshToken: config.Notification.Teams.RefreshToken,
+			GraphURL:     config.Notification.Teams.GraphURL,
+			LoginURL:     config.Notification.Teams.LoginURL,
+			Timeout:      config.Notification.Teams.Timeout * time.Second,
+		})
+		return notif, retry.Options{RetryAfter: httperr.RetryAfter, Retryable: teams.Retryable}, err
+	case "discord":
+		notif, err := discord.NewNotification(discord.Options{
+			Token:          config.Notification.Discord.Token,
+			Forums:         config.Notification.Discord.Forums,
+			GuildID:        config.Notification.Discord.GuildID,
+			ArchiveMinutes: config.Notification.Discord.ArchiveMinutes,
+			APIURL:         config.Notification.Discord.APIURL,
+			Timeout:        config.Notification.Discord.Timeout * time.Second,
+		})
+		return notif, retry.Options{RetryAfter: httperr.RetryAfter, Retryable: discord.Retryable}, err
+	case "email":
+		notif, err := email.NewNotification(email.Options{
+			Host:       config.Notification.Email.Host,
+			Port:       config.Notification.Email.Port,
+			TLS:        config.Notification.Email.TLS,
+			Username:   config.Notification.Email.Username,
+			Password:   config.Notification.Email.Password,
+			From:       config.Notification.Email.From,
+			Recipients: config.Notification.Email.Recipients,
+			Timeout:    config.Notification.Email.Timeout * time.Second,
+		})
+		return notif, retry.Options{Retryable: email.Retryable}, err
+	case "webhook":
+		notif, err := webhook.NewNotification(webhook.Options{
+			URL:             config.Notification.Webhook.URL,
+			ParentURL:       config.Notification.Webhook.ParentURL,
+			ReplyURL:        config.Notification.Webhook.ReplyURL,
+			UpdateURL:       config.Notification.Webhook.UpdateURL,
+			Headers:         config.Notification.Webhook.Headers,
+			Secret:          config.Notification.Webhook.Secret,
+			SignatureHeader: config.Notification.Webhook.SignatureHeader,
+			Timeout:         config.Notification.Webhook.Timeout * time.Second,
+		})
+		return notif, retry.Options{RetryAfter: httperr.RetryAfter, Retryable: httperr.Retryable}, err
+	}
+
+	return nil, retry.Options{}, fmt.Errorf("Unknown notification type %s", notifType)
+}
+
+func initBridge(config Bridge) (usecase.Bridge, error) {
+	switch config.Type {
+	case "", bridge.TypeNone:
+		return nil, nil
+	case bridge.TypeMeet:
+		return bridge.NewMeet(bridge.MeetOptions{
+			Prefix: config.Meet.Prefix,
+			Name:   config.Meet.Name,
+		})
+	case bridge.TypeZoom:
+		return bridge.NewZoom(bridge.ZoomOptions{
+			Rooms:   config.Zoom.Rooms,
+			Default: config.Zoom.Default,
+		})
+	case bridge.TypeJitsi:
+		return bridge.NewJitsi(bridge.JitsiOptions{
+			Server:     config.Jitsi.Server,
+			RoomPrefix: config.Jitsi.RoomPrefix,
+		})
+	}
+
+	return nil, fmt.Errorf("Unknown bridge type %s", config.Type)
+}
+
 func readConfig(path string) (*Config, error) {
 	content, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -3,8 +3,11 @@ module github.com/alvintzz/alert-thread
 go 1.16
 
 require (
+	github.com/alicebob/miniredis/v2 v2.14.3
 	github.com/go-chi/chi v1.5.4
+	github.com/go-redis/redis/v8 v8.11.4
 	github.com/sirupsen/logrus v1.8.1
 	github.com/slack-go/slack v0.9.1
-	gopkg.in/h2non/gock.v1 v1.1.0 // indirect
+	go.etcd.io/bbolt v1.3.6
+	gopkg.in/h2non/gock.v1 v1.1.0
 )
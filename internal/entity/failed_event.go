@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FailedEvent contains information of vendor notification which is failed to be processed so it can be replayed later
+type FailedEvent struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Vendor string `json:"vendor"`
+
+	// Webhook and Query are the webhook receiving the notification and its URL query so the raw Payload can be decoded again on replay
+	Webhook string          `json:"webhook,omitempty"`
+	Query   string          `json:"query,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failed_at"`
+
+	// Channels are the channels failed to be delivered so only they are delivered again on replay. Empty is every channel of the incident
+	Channels []string `json:"channels,omitempty"`
+}
+
+// Source is the raw webhook request a vendor notification is decoded from
+type Source struct {
+	// Webhook is name of the webhook receiving the request, e.g. datadog or generic/<mapping name>
+	Webhook string
+	Query   string
+	Payload []byte
+}
+
+// GetSource will return the raw webhook request. Vendor notification embedding Source implements Sourced
+func (s Source) GetSource() Source {
+	return s
+}
+
+// Sourced is optional interface of ReplyInThread which keeps the raw webhook request it is decoded from
+type Sourced interface {
+	GetSource() Source
+}
@@ -1,14 +1,15 @@
 package entity
 
 import (
+	"strings"
 	"time"
 )
 
 // IncidentStatus contain the information of each incident status
 type IncidentStatus struct {
-	Code    string
-	Message string
-	Color   string
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Color   string `json:"color"`
 }
 
 // IsRecovered check whether this incident is marked as recovered or not
@@ -25,13 +26,89 @@ var (
 
 	//StatusRecovered is a status when the vendor mark an alert as RECOVER
 	StatusRecovered = IncidentStatus{Code: "recover", Message: "Recovered", Color: "00BF85"}
+
+	//StatusAcknowledged is a status when someone already acknowledged the alert but it is not recovered yet
+	StatusAcknowledged = IncidentStatus{Code: "acknowledged", Message: "Acknowledged", Color: "439FE0"}
 )
 
+// statuses is list of every known incident status
+var statuses = []IncidentStatus{StatusWarning, StatusTriggered, StatusRecovered, StatusAcknowledged}
+
+// ParseStatus will return incident status which code or message is matched with the value case-insensitively
+func ParseStatus(value string) (IncidentStatus, bool) {
+	for _, status := range statuses {
+		if strings.EqualFold(status.Code, value) || strings.EqualFold(status.Message, value) {
+			return status, true
+		}
+	}
+
+	return IncidentStatus{}, false
+}
+
+// FormatTime will return the time in UTC shown inside the notification and command response
+func FormatTime(value time.Time) string {
+	return value.UTC().Format("2006-01-02 15:04 MST")
+}
+
+// Thread contain information of the thread created for an incident in a notification channel
+type Thread struct {
+	Channel  string `json:"channel"`
+	ThreadID string `json:"thread_id"`
+
+	// Title and Summary are the texts rendered for the channel. Empty value falls back to title and summary of the incident
+	Title   string `json:"title,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// Bridge contain information of conference bridge of an incident
+type Bridge struct {
+	// Name is the conference provider shown beside the link, e.g. Meet
+	Name string `json:"name"`
+	Link string `json:"link"`
+}
+
 // Incident contain information of incident got from vendor data
 type Incident struct {
-	Title      string
-	ThreadID   string
-	Vendor     string
-	Status     IncidentStatus
-	LastUpdate time.Time
+	Title      string         `json:"title"`
+	Summary    string         `json:"summary"`
+	Threads    []Thread       `json:"threads"`
+	Vendor     string         `json:"vendor"`
+	Status     IncidentStatus `json:"status"`
+	LastUpdate time.Time      `json:"last_update"`
+
+	// ResolvedAt is when the incident is recovered. It is cleared when the incident is triggered again
+	ResolvedAt time.Time `json:"resolved_at"`
+
+	// ClosedAt is when the incident is closed manually without waiting for the grace period
+	ClosedAt time.Time `json:"closed_at"`
+
+	// AcknowledgedBy and ResolvedBy are user who acknowledged or resolved the incident from the notification channel
+	AcknowledgedBy string    `json:"acknowledged_by"`
+	AcknowledgedAt time.Time `json:"acknowledged_at"`
+	ResolvedBy     string    `json:"resolved_by"`
+
+	// SnoozedUntil is until when the replies of the incident are suppressed
+	SnoozedUntil time.Time `json:"snoozed_until"`
+
+	// Bridge is the conference where the incident is discussed. It is generated once so the link is kept for the whole incident
+	Bridge Bridge `json:"bridge"`
+
+	// PreviousThreads is threads of the closed incident with the same key this incident is reopened from
+	PreviousThreads []Thread `json:"previous_threads"`
+
+	// DetailHash and LastReply describe the last reply sent to the threads, Repeats is number of identical notification suppressed after it
+	DetailHash string    `json:"detail_hash"`
+	LastReply  time.Time `json:"last_reply"`
+	Repeats    int       `json:"repeats"`
+}
+
+// GetThread will return thread of the incident created in the channel
+func (i Incident) GetThread(channel string) (Thread, bool) {
+	for _, thread := range i.Threads {
+		if thread.Channel == channel {
+			return thread, true
+		}
+	}
+
+	return Thread{}, false
 }
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"testing"
+	"time"
 )
 
 func TestIsRecovered(t *testing.T) {
@@ -11,7 +12,29 @@ func TestIsRecovered(t *testing.T) {
 	if StatusTriggered.IsRecovered() {
 		t.Errorf("status %s got %t instead", StatusWarning.Message, StatusWarning.IsRecovered())
 	}
+	if StatusAcknowledged.IsRecovered() {
+		t.Errorf("status %s got %t instead", StatusAcknowledged.Message, StatusAcknowledged.IsRecovered())
+	}
 	if !StatusRecovered.IsRecovered() {
 		t.Errorf("status %s got %t instead", StatusWarning.Message, StatusWarning.IsRecovered())
 	}
 }
+
+func TestParseStatus(t *testing.T) {
+	values := []string{"warning", "Triggered", "ERROR", "recover", "recovered", "acknowledged", "unknown"}
+	expected := []IncidentStatus{StatusWarning, StatusTriggered, StatusTriggered, StatusRecovered, StatusRecovered, StatusAcknowledged, {}}
+
+	for k, value := range values {
+		status, ok := ParseStatus(value)
+		if status != expected[k] || ok != (expected[k] != IncidentStatus{}) {
+			t.Errorf("value %s expecting %+v, got %+v (%t) instead", value, expected[k], status, ok)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	value := time.Date(2021, 3, 4, 12, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+	if result := FormatTime(value); result != "2021-03-04 05:30 UTC" {
+		t.Errorf("time %s expecting 2021-03-04 05:30 UTC, got %s instead", value, result)
+	}
+}
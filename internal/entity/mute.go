@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// Mute contains pattern of incident keys which notifications are suppressed until the given time
+type Mute struct {
+	ID        string    `json:"id"`
+	Pattern   string    `json:"pattern"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	Until     time.Time `json:"until"`
+}
+
+// IsActive check whether the mute is still suppressing notifications at the given time
+func (m Mute) IsActive(now time.Time) bool {
+	return now.Before(m.Until)
+}
+
+// Match check whether the incident key is matched with the mute pattern case-insensitively
+func (m Mute) Match(key string) bool {
+	matched, err := path.Match(strings.ToLower(m.Pattern), strings.ToLower(key))
+	return err == nil && matched
+}
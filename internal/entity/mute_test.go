@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMute(t *testing.T) {
+	now := time.Now()
+	mute := Mute{Pattern: "DB-*", Until: now.Add(time.Hour)}
+
+	keys := []string{"db-primary", "DB-replica", "cpu-db", "db"}
+	expected := []bool{true, true, false, false}
+	for k, key := range keys {
+		if mute.Match(key) != expected[k] {
+			t.Errorf("key %s expecting matched %t, got %t instead", key, expected[k], mute.Match(key))
+		}
+	}
+
+	if !mute.IsActive(now) || mute.IsActive(now.Add(2*time.Hour)) {
+		t.Errorf("mute until %s expecting to be active only before it", mute.Until)
+	}
+	if (Mute{Pattern: "["}).Match("[") {
+		t.Errorf("invalid pattern expecting not to match any key")
+	}
+}
@@ -2,14 +2,25 @@ package entity
 
 // Notification contains information of what we want to send to notification channel
 type Notification struct {
-	Channel  string
-	Title    string
-	Message  string
-	Color    string
-	Image    string
-	Metadata map[string]string
+	Key      string            `json:"key"`
+	Channel  string            `json:"channel"`
+	Title    string            `json:"title"`
+	Message  string            `json:"message"`
+	Color    string            `json:"color"`
+	Image    string            `json:"image"`
+	Metadata map[string]string `json:"metadata"`
+
+	// Resolved tells the incident is recovered or closed, so it is no longer acted on from the main thread
+	Resolved bool `json:"resolved"`
 }
 
+// Action of incident triggered by user from interactive notification such as Slack button
+const (
+	ActionAcknowledge = "acknowledge"
+	ActionResolve     = "resolve"
+	ActionSnooze      = "snooze"
+)
+
 const defaultColor = "FFFFFF"
 
 // GetColor will return default color white if the color is not specified
@@ -12,4 +12,5 @@ type ReplyInThread interface {
 	GetStatus() IncidentStatus
 	GetImage() string
 	GetChannel() string
+	GetTags() []string
 }
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// AlertmanagerAlert is object to cater single alert inside Alertmanager request parameter
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     string            `json:"startsAt"`
+	EndsAt       string            `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerReplyThread is object to cater Alertmanager v4 request parameter. One request is one alert group
+type AlertmanagerReplyThread struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+	Vendor            string
+	Channel           string `json:"channel"`
+	KeyLabels         []string
+
+	entity.Source `json:"-"`
+}
+
+// GetKey will return the alert group identifier. When key labels are configured, the group is identified by those label values instead of groupKey
+func (r AlertmanagerReplyThread) GetKey() string {
+	values := []string{}
+	for _, label := range r.KeyLabels {
+		value, ok := r.CommonLabels[label]
+		if !ok {
+			value, ok = r.GroupLabels[label]
+		}
+		if ok {
+			values = append(values, fmt.Sprintf("%s=%s", label, value))
+		}
+	}
+	if len(values) == 0 {
+		return r.GroupKey
+	}
+
+	return strings.Join(values, ",")
+}
+
+// GetChannel will return specified Notification Channel ID from Alertmanager Object
+func (r AlertmanagerReplyThread) GetChannel() string {
+	return r.Channel
+}
+
+// GetTags will return every common label of the alert group as "name:value" tag
+func (r AlertmanagerReplyThread) GetTags() []string {
+	return labelTags(r.CommonLabels)
+}
+
+// GetVendor will return from which vendor this parameter is which is always Alertmanager in this case
+func (r AlertmanagerReplyThread) GetVendor() string {
+	return r.Vendor
+}
+
+// GetTitle will return title string for notification message. In Slack Notification, this title will be visible in the slack notification pop-up
+func (r AlertmanagerReplyThread) GetTitle() string {
+	title := r.CommonLabels["alertname"]
+	if title == "" {
+		title = formatLabels(r.GroupLabels)
+	}
+	if r.ExternalURL == "" {
+		return fmt.Sprintf("*%s*", escapeComparison(title))
+	}
+
+	return fmt.Sprintf("*<%s|%s>*", r.ExternalURL, escapeComparison(title))
+}
+
+// GetStatus will return status of the alert group. The group is only a warning when every firing alert has warning severity
+func (r AlertmanagerReplyThread) GetStatus() entity.IncidentStatus {
+	if r.Status == "resolved" {
+		return entity.StatusRecovered
+	}
+
+	status := entity.StatusTriggered
+	for _, alert := range r.Alerts {
+		if alert.Status != "firing" {
+			continue
+		}
+		if !strings.EqualFold(alert.Labels["severity"], "warning") {
+			return entity.StatusTriggered
+		}
+		status = entity.StatusWarning
+	}
+
+	return status
+}
+
+// GetSummary will return at-glance facts specific to the vendor which the default summary template shows below vendor and status, i.e. number of firing and resolved alerts
+func (r AlertmanagerReplyThread) GetSummary() string {
+	firing, resolved := 0, 0
+	for _, alert := range r.Alerts {
+		if alert.Status == "resolved" {
+			resolved++
+		} else {
+			firing++
+		}
+	}
+
+	return fmt.Sprintf("Alerts: *%d firing, %d resolved*", firing, resolved)
+}
+
+// GetDetail will return detail of every alert inside the group. This message will be shown in the threads and can contain more detail incident data
+func (r AlertmanagerReplyThread) GetDetail() string {
+	details := []string{}
+	for _, alert := range r.Alerts {
+		lines := []string{fmt.Sprintf("*[%s]* %s", strings.ToUpper(alert.Status), formatLabels(alert.Labels))}
+		if summary := alert.Annotations["summary"]; summary != "" {
+			lines = append(lines, summary)
+		}
+		if description := alert.Annotations["description"]; description != "" {
+			lines = append(lines, description)
+		}
+		if alert.GeneratorURL != "" {
+			lines = append(lines, fmt.Sprintf("<%s|Source>", alert.GeneratorURL))
+		}
+
+		details = append(details, strings.Join(lines, "\n"))
+	}
+	if r.TruncatedAlerts > 0 {
+		details = append(details, fmt.Sprintf("_%d more alerts are truncated_", r.TruncatedAlerts))
+	}
+
+	return strings.Join(details, "\n\n")
+}
+
+// GetImage will return empty string as Alertmanager does not provide any metric snapshot
+func (r AlertmanagerReplyThread) GetImage() string {
+	return ""
+}
+
+// formatLabels will return sorted labels in key=value format
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+
+	return strings.Join(pairs, ", ")
+}
+
+// AlertmanagerReplyInThread receive callback request from Alertmanager and parse the alert group into ReplyInThread interface and call the usecase function
+func (s *Handler) AlertmanagerReplyInThread(w http.ResponseWriter, r *http.Request) {
+	s.receive(w, r, webhookAlertmanager)
+}
+
+// decodeAlertmanager will parse raw request of Alertmanager into ReplyInThread object of the alert group
+func (s *Handler) decodeAlertmanager(source entity.Source, query url.Values) ([]entity.ReplyInThread, error) {
+	request := AlertmanagerReplyThread{}
+	err := json.Unmarshal(source.Payload, &request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal body because %s", err)
+	}
+	request.Vendor = vendorAlertmanager
+	request.Source = source
+	request.KeyLabels = s.options.AlertmanagerGroupBy
+	request.Channel = request.CommonLabels["channel"]
+	if request.Channel == "" {
+		request.Channel = query.Get("channel")
+	}
+
+	return []entity.ReplyInThread{request}, nil
+}
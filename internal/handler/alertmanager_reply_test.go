@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+)
+
+var flowAlertmanagerReply = "alertmanager reply in thread flow"
+
+var alertmanagerPayload = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighLatency\"}",
+	"status": "%s",
+	"receiver": "alert-thread",
+	"groupLabels": {"alertname": "HighLatency"},
+	"commonLabels": {"alertname": "HighLatency", "service": "checkout", "channel": "C_LABEL"},
+	"externalURL": "http://alertmanager:9093",
+	"alerts": [
+		{"status": "%s", "labels": {"alertname": "HighLatency", "instance": "a", "severity": "warning"}, "annotations": {"summary": "Latency is high on a"}, "generatorURL": "http://prometheus/graph?a"},
+		{"status": "resolved", "labels": {"alertname": "HighLatency", "instance": "b", "severity": "critical"}, "annotations": {"description": "Latency is back on b"}}
+	]
+}`
+
+func TestAlertmanagerReplyInThread(t *testing.T) {
+	uc := &usecaseMock{params: make(chan entity.ReplyInThread, 10)}
+
+	statuses := []string{"firing", "resolved"}
+	expected := []entity.IncidentStatus{entity.StatusWarning, entity.StatusRecovered}
+	for k, status := range statuses {
+		h := New(uc, queue.New(1, 10), Options{})
+		payload := fmt.Sprintf(alertmanagerPayload, status, status)
+
+		req := httptest.NewRequest(http.MethodPost, "/webhook/alertmanager/reply-in-thread", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		h.AlertmanagerReplyInThread(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf(messageNotExpect, flowAlertmanagerReply, status, http.StatusOK, rec.Code)
+		}
+
+		param := uc.receive(t, 1)[0]
+		if param.GetKey() != `{}:{alertname="HighLatency"}` {
+			t.Errorf(messageNotExpect, flowAlertmanagerReply, status, `{}:{alertname="HighLatency"}`, param.GetKey())
+		}
+		if param.GetStatus() != expected[k] {
+			t.Errorf(messageNotExpect, flowAlertmanagerReply, status, expected[k], param.GetStatus())
+		}
+		if param.GetChannel() != "C_LABEL" {
+			t.Errorf(messageNotExpect, flowAlertmanagerReply, status, "C_LABEL", param.GetChannel())
+		}
+		if !strings.Contains(param.GetDetail(), "Latency is high on a") || !strings.Contains(param.GetDetail(), "*[RESOLVED]* alertname=HighLatency, instance=b, severity=critical") {
+			t.Errorf(messageNotExpect, flowAlertmanagerReply, status, "every alert in detail", param.GetDetail())
+		}
+	}
+
+	h := New(uc, queue.New(1, 10), Options{AlertmanagerGroupBy: []string{"service", "missing"}})
+	payload := fmt.Sprintf(alertmanagerPayload, "firing", "firing")
+	req := httptest.NewRequest(http.MethodPost, "/webhook/alertmanager/reply-in-thread", strings.NewReader(payload))
+	h.AlertmanagerReplyInThread(httptest.NewRecorder(), req)
+
+	param := uc.receive(t, 1)[0]
+	if param.GetKey() != "service=checkout" {
+		t.Errorf(messageNotExpect, flowAlertmanagerReply, "group by", "service=checkout", param.GetKey())
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// AuthConfig defines how request of a webhook group is authenticated. Every configured method must pass, and empty config accept every request
+type AuthConfig struct {
+	// Secret is shared secret expected in Header or QueryParam
+	Secret     string `json:"secret"`
+	Header     string `json:"header"`
+	QueryParam string `json:"query_param"`
+
+	// BearerToken is expected token inside "Authorization: Bearer" header
+	BearerToken string `json:"bearer_token"`
+
+	// BasicUsername and BasicPassword are expected credential inside "Authorization: Basic" header
+	BasicUsername string `json:"basic_username"`
+	BasicPassword string `json:"basic_password"`
+
+	// HMACSecret is the key of hex encoded HMAC-SHA256 signature of request body sent in HMACHeader. HMACPrefix such as "sha256=" is stripped from the signature
+	HMACSecret string `json:"hmac_secret"`
+	HMACHeader string `json:"hmac_header"`
+	HMACPrefix string `json:"hmac_prefix"`
+
+	// AllowCIDRs is list of network allowed to call the webhook
+	AllowCIDRs []string `json:"allow_cidrs"`
+}
+
+// Configured will check whether any authentication method is configured, otherwise every request is accepted
+func (c AuthConfig) Configured() bool {
+	return c.Secret != "" || c.BearerToken != "" || c.BasicUsername != "" || c.BasicPassword != "" || c.HMACSecret != "" || len(c.AllowCIDRs) > 0
+}
+
+// authenticator contains parsed AuthConfig of a webhook group
+type authenticator struct {
+	name     string
+	config   AuthConfig
+	networks []*net.IPNet
+}
+
+// NewAuthMiddleware will return middleware rejecting request of the webhook group which is not matched with the config
+func NewAuthMiddleware(name string, config AuthConfig) (func(http.Handler) http.Handler, error) {
+	if config.Secret != "" && config.Header == "" && config.QueryParam == "" {
+		return nil, fmt.Errorf("Header or query param of %s secret is required", name)
+	}
+	if config.HMACSecret != "" && config.HMACHeader == "" {
+		return nil, fmt.Errorf("HMAC header of %s is required", name)
+	}
+
+	networks := make([]*net.IPNet, 0, len(config.AllowCIDRs))
+	for _, cidr := range config.AllowCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse CIDR %s of %s because %s", cidr, name, err)
+		}
+		networks = append(networks, network)
+	}
+
+	auth := &authenticator{
+		name:     name,
+		config:   config,
+		networks: networks,
+	}
+
+	return auth.middleware, nil
+}
+
+func (a *authenticator) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.allowed(r) {
+			log.Warnf("Rejected %s request from %s because the address is not allowed", a.name, r.RemoteAddr)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		reason, err := a.authenticate(r)
+		if err != nil {
+			log.Errorf("Failed to authenticate %s request because %s", a.name, err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if reason != "" {
+			log.Warnf("Rejected %s request from %s because %s", a.name, r.RemoteAddr, reason)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowed will check whether the request address is inside the allowed networks
+func (a *authenticator) allowed(r *http.Request) bool {
+	if len(a.networks) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range a.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authenticate will return the reason why the request is rejected or empty string when it is accepted
+func (a *authenticator) authenticate(r *http.Request) (string, error) {
+	if a.config.Secret != "" {
+		token := ""
+		if a.config.Header != "" {
+			token = r.Header.Get(a.config.Header)
+		}
+		if token == "" && a.config.QueryParam != "" {
+			token = r.URL.Query().Get(a.config.QueryParam)
+		}
+		if !secureEqual(token, a.config.Secret) {
+			return "shared secret is not matched", nil
+		}
+	}
+
+	if a.config.BearerToken != "" {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return "bearer token is missing", nil
+		}
+		if !secureEqual(strings.TrimPrefix(header, "Bearer "), a.config.BearerToken) {
+			return "bearer token is not matched", nil
+		}
+	}
+
+	if a.config.BasicUsername != "" || a.config.BasicPassword != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || !secureEqual(username, a.config.BasicUsername) || !secureEqual(password, a.config.BasicPassword) {
+			return "basic auth credential is not matched", nil
+		}
+	}
+
+	if a.config.HMACSecret != "" {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read body because %s", err)
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(a.config.HMACHeader), a.config.HMACPrefix))
+		if err != nil {
+			return "HMAC signature is not a hex string", nil
+		}
+
+		mac := hmac.New(sha256.New, []byte(a.config.HMACSecret))
+		mac.Write(body)
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return "HMAC signature is not matched", nil
+		}
+	}
+
+	return "", nil
+}
+
+// secureEqual will compare both string in constant time so the secret can not be guessed from the response time
+func secureEqual(actual, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
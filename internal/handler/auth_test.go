@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var flowAuth = "auth middleware flow"
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewAuthMiddleware(t *testing.T) {
+	configs := []AuthConfig{
+		{},
+		{Secret: "secret", Header: "X-Token"},
+		{Secret: "secret"},
+		{HMACSecret: "secret"},
+		{AllowCIDRs: []string{"10.0.0.0/8", "::1/128"}},
+		{AllowCIDRs: []string{"10.0.0.0"}},
+	}
+	expected := []bool{true, true, false, false, true, false}
+
+	for k, config := range configs {
+		_, err := NewAuthMiddleware("datadog", config)
+		if (err == nil) != expected[k] {
+			t.Errorf(messageNotExpect, flowAuth, fmt.Sprintf("config %d", k), expected[k], err)
+		}
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	body := `{"cycle_key": "key"}`
+	configs := []AuthConfig{
+		{},
+		{Secret: "secret", Header: "X-Token", QueryParam: "token"},
+		{BearerToken: "token"},
+		{BasicUsername: "user", BasicPassword: "pass"},
+		{HMACSecret: "secret", HMACHeader: "X-Signature", HMACPrefix: "sha256="},
+		{AllowCIDRs: []string{"10.0.0.0/8"}},
+	}
+
+	requests := [][]func(r *http.Request){
+		{func(r *http.Request) {}},
+		{
+			func(r *http.Request) { r.Header.Set("X-Token", "secret") },
+			func(r *http.Request) { r.URL.RawQuery = "token=secret" },
+			func(r *http.Request) { r.Header.Set("X-Token", "wrong") },
+			func(r *http.Request) {},
+		},
+		{
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") },
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer wrong") },
+			func(r *http.Request) { r.Header.Set("Authorization", "token") },
+		},
+		{
+			func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+			func(r *http.Request) { r.SetBasicAuth("user", "wrong") },
+			func(r *http.Request) {},
+		},
+		{
+			func(r *http.Request) { r.Header.Set("X-Signature", "sha256="+sign("secret", body)) },
+			func(r *http.Request) { r.Header.Set("X-Signature", "sha256="+sign("wrong", body)) },
+			func(r *http.Request) { r.Header.Set("X-Signature", "not-hex") },
+		},
+		{
+			func(r *http.Request) { r.RemoteAddr = "10.1.2.3:5000" },
+			func(r *http.Request) { r.RemoteAddr = "192.168.1.1:5000" },
+		},
+	}
+
+	expected := [][]int{
+		{http.StatusOK},
+		{http.StatusOK, http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized},
+		{http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized},
+		{http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized},
+		{http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized},
+		{http.StatusOK, http.StatusForbidden},
+	}
+
+	for k, config := range configs {
+		middleware, err := NewAuthMiddleware("datadog", config)
+		if err != nil {
+			t.Fatalf(messageNotExpect, flowAuth, fmt.Sprintf("config %d", k), nil, err)
+		}
+
+		// Next handler must still be able to read the body after the signature is checked
+		next := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ := ioutil.ReadAll(r.Body)
+			if string(received) != body {
+				t.Errorf(messageNotExpect, flowAuth, fmt.Sprintf("config %d body", k), body, string(received))
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		for i, modify := range requests[k] {
+			req := httptest.NewRequest(http.MethodPost, "/webhook/datadog/reply-in-thread", strings.NewReader(body))
+			modify(req)
+
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, req)
+			if rec.Code != expected[k][i] {
+				t.Errorf(messageNotExpect, flowAuth, fmt.Sprintf("config %d request %d", k, i), expected[k][i], rec.Code)
+			}
+		}
+	}
+}
+
+func TestAuthConfigured(t *testing.T) {
+	configs := []AuthConfig{
+		{},
+		{Header: "X-Token", QueryParam: "token"},
+		{BearerToken: "token"},
+		{BasicUsername: "user"},
+		{HMACSecret: "secret", HMACHeader: "X-Signature"},
+		{AllowCIDRs: []string{"10.0.0.0/8"}},
+	}
+	expected := []bool{false, false, true, true, true, true}
+
+	for k, config := range configs {
+		if config.Configured() != expected[k] {
+			t.Errorf(messageNotExpect, flowAuth, fmt.Sprintf("configured %d", k), expected[k], config.Configured())
+		}
+	}
+}
@@ -1,17 +1,13 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/alvintzz/alert-thread/internal/entity"
-
-	log "github.com/sirupsen/logrus"
 )
 
 // DatadogReplyThread is object to cater Datadog request parameter
@@ -27,6 +23,8 @@ type DatadogReplyThread struct {
 	Vendor    string
 	Channel   string `json:"channel"`
 	Tags      string `json:"tags"`
+
+	entity.Source `json:"-"`
 }
 
 // GetKey will return the incident unique id as identifier whether the incident should go to same thread or not
@@ -39,6 +37,11 @@ func (r DatadogReplyThread) GetChannel() string {
 	return r.Channel
 }
 
+// GetTags will return every tag of the monitor sent by Datadog as comma separated string
+func (r DatadogReplyThread) GetTags() []string {
+	return splitTags(r.Tags)
+}
+
 // GetVendor will return from which vendor this parameter is which is always Datadog in this case
 func (r DatadogReplyThread) GetVendor() string {
 	return r.Vendor
@@ -46,20 +49,7 @@ func (r DatadogReplyThread) GetVendor() string {
 
 // GetTitle will return title string for notification message. In Slack Notification, this title will be visible in the slack notification pop-up
 func (r DatadogReplyThread) GetTitle() string {
-	if strings.Contains(r.Title, ">=") {
-		r.Title = strings.Replace(r.Title, ">=", "more/equal than", -1)
-	}
-	if strings.Contains(r.Title, ">") {
-		r.Title = strings.Replace(r.Title, ">", "more than", -1)
-	}
-	if strings.Contains(r.Title, "<=") {
-		r.Title = strings.Replace(r.Title, "<=", "less/equal than", -1)
-	}
-	if strings.Contains(r.Title, "<") {
-		r.Title = strings.Replace(r.Title, "<", "less than", -1)
-	}
-
-	return fmt.Sprintf("*<%s|%s>*", r.GetURL(), r.Title)
+	return fmt.Sprintf("*<%s|%s>*", r.GetURL(), escapeComparison(r.Title))
 }
 
 // GetStatus will return status of the incident
@@ -73,12 +63,9 @@ func (r DatadogReplyThread) GetStatus() entity.IncidentStatus {
 	return entity.StatusTriggered
 }
 
-// GetSummary will return summary string for notification header. This message will be shown in the main thread and should contain at-glance summary of incident
+// GetSummary will return at-glance facts specific to the vendor which the default summary template shows below vendor and status. Datadog has none
 func (r DatadogReplyThread) GetSummary() string {
-	hangoutLink := fmt.Sprintf("http://g.co/meet/tkpd-%s", r.GetKey())
-	summary := fmt.Sprintf("*Hangout Link* : %s\n\nFrom: *%s*\nCurrent Status: *%s*", hangoutLink, r.GetVendor(), r.GetStatus().Message)
-
-	return summary
+	return ""
 }
 
 // GetURL is helper function to get datadog monitor URL
@@ -128,23 +115,18 @@ func (r DatadogReplyThread) GetImage() string {
 
 // DdogReplyInThread receive callback request from Datadog and parse the request into ReplyInThread interface and call the usecase function
 func (s *Handler) DdogReplyInThread(w http.ResponseWriter, r *http.Request) {
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		log.Errorf("Failed to read body because %s", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
+	s.receive(w, r, webhookDatadog)
+}
 
+// decodeDatadog will parse raw request of Datadog into ReplyInThread object
+func (s *Handler) decodeDatadog(source entity.Source) ([]entity.ReplyInThread, error) {
 	request := DatadogReplyThread{}
-	err = json.Unmarshal(body, &request)
+	err := json.Unmarshal(source.Payload, &request)
 	if err != nil {
-		log.Errorf("Failed to unmarshal body because %s", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, fmt.Errorf("Failed to unmarshal body because %s", err)
 	}
-	request.Vendor = "Datadog"
-
-	go s.usecase.ReplyInThread(context.Background(), request)
+	request.Vendor = vendorDatadog
+	request.Source = source
 
-	w.WriteHeader(http.StatusOK)
+	return []entity.ReplyInThread{request}, nil
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	"github.com/go-chi/chi"
+	log "github.com/sirupsen/logrus"
+)
+
+// decodeFailedEvent will turn the raw request saved inside failed event back into ReplyInThread object of its incident
+func (s *Handler) decodeFailedEvent(event entity.FailedEvent) (entity.ReplyInThread, error) {
+	params, err := s.decode(entity.Source{Webhook: event.Webhook, Query: event.Query, Payload: event.Payload})
+	if err != nil {
+		return nil, err
+	}
+
+	// Single request may contain several alerts, e.g. Grafana, so only the alert of the failed incident is replayed
+	for _, param := range params {
+		if param.GetKey() == event.Key {
+			return param, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Request of webhook %s has no alert of %s", event.Webhook, event.Key)
+}
+
+// ListFailedEvents return every notification failed to be processed
+func (s *Handler) ListFailedEvents(w http.ResponseWriter, r *http.Request) {
+	events, err := s.usecase.ListFailedEvents(r.Context())
+	if err != nil {
+		log.Errorf("Failed to list failed events because %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+// GetFailedEvent return single notification failed to be processed including its original payload
+func (s *Handler) GetFailedEvent(w http.ResponseWriter, r *http.Request) {
+	event, ok := s.getFailedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, event)
+}
+
+// ReplayFailedEvent process the failed notification again into its incident thread
+func (s *Handler) ReplayFailedEvent(w http.ResponseWriter, r *http.Request) {
+	event, ok := s.getFailedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	param, err := s.decodeFailedEvent(event)
+	if err != nil {
+		log.Errorf("Failed to decode payload of failed event %s because %s", event.ID, err.Error())
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	err = s.usecase.ReplayFailedEvent(r.Context(), event, param)
+	if err != nil {
+		log.Errorf("Failed to replay failed event %s because %s", event.ID, err.Error())
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// DiscardFailedEvent remove the failed notification without processing it
+func (s *Handler) DiscardFailedEvent(w http.ResponseWriter, r *http.Request) {
+	event, ok := s.getFailedEvent(w, r)
+	if !ok {
+		return
+	}
+
+	err := s.usecase.DiscardFailedEvent(r.Context(), event.ID)
+	if err != nil {
+		log.Errorf("Failed to discard failed event %s because %s", event.ID, err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getFailedEvent will return failed event of id inside the URL and write the error response when it is not available
+func (s *Handler) getFailedEvent(w http.ResponseWriter, r *http.Request) (entity.FailedEvent, bool) {
+	id := chi.URLParam(r, "id")
+	event, err := s.usecase.GetFailedEvent(r.Context(), id)
+	if err != nil {
+		log.Errorf("Failed to get failed event %s because %s", id, err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return entity.FailedEvent{}, false
+	}
+	if event.ID == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return entity.FailedEvent{}, false
+	}
+
+	return event, true
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+
+	"github.com/go-chi/chi"
+)
+
+var flowFailedEvent = "failed event flow"
+
+func newFailedEventRequest(method, id string) *http.Request {
+	req := httptest.NewRequest(method, "/admin/failed-events/"+id, nil)
+
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add("id", id)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
+
+func TestDecodeFailedEvent(t *testing.T) {
+	// Both mappings share the vendor name, so only the webhook name tells which mapping decodes the request
+	ci, _ := NewGenericMapping("ci", GenericConfig{Vendor: "CI", Key: "{{.id}}"})
+	cd, _ := NewGenericMapping("cd", GenericConfig{Vendor: "CI", Key: "cd-{{.id}}"})
+	h := New(&usecaseMock{}, queue.New(1, 1), Options{Generic: map[string]*GenericMapping{"ci": ci, "cd": cd}})
+
+	events := []entity.FailedEvent{
+		{Key: "datadog_key", Webhook: webhookDatadog, Payload: []byte(`{"cycle_key": "datadog_key"}`)},
+		{Key: "second", Webhook: webhookGrafana, Query: "channel=C1", Payload: []byte(`{"alerts": [{"fingerprint": "first"}, {"fingerprint": "second"}]}`)},
+		{Key: "42", Webhook: webhookNewRelic, Payload: []byte(`{"incident_id": 42}`)},
+		{Key: "group", Webhook: webhookAlertmanager, Payload: []byte(`{"groupKey": "group"}`)},
+		{Key: "cd-7", Webhook: webhookGeneric + "cd", Payload: []byte(`{"id": 7}`)},
+	}
+	for _, event := range events {
+		param, err := h.decodeFailedEvent(event)
+		if err != nil {
+			t.Errorf(messageNotExpect, flowFailedEvent, event.Key, nil, err)
+			continue
+		}
+		if param.GetKey() != event.Key {
+			t.Errorf(messageNotExpect, flowFailedEvent, event.Key, event.Key, param.GetKey())
+		}
+		if sourced, ok := param.(entity.Sourced); ok && string(sourced.GetSource().Payload) != string(event.Payload) {
+			t.Errorf(messageNotExpect, flowFailedEvent, event.Key, string(event.Payload), string(sourced.GetSource().Payload))
+		}
+	}
+	if param, _ := h.decodeFailedEvent(events[1]); param.GetChannel() != "C1" {
+		t.Errorf(messageNotExpect, flowFailedEvent, "query", "C1", param.GetChannel())
+	}
+
+	failures := []entity.FailedEvent{
+		{Key: "unknown", Webhook: "unknown", Payload: []byte(`{}`)},
+		{Key: "7", Webhook: webhookGeneric + "unknown", Payload: []byte(`{"id": 7}`)},
+		{Key: "missing", Webhook: webhookGrafana, Payload: []byte(`{"alerts": [{"fingerprint": "first"}]}`)},
+		{Key: "generic_key", Vendor: "CI", Payload: []byte(`{"Key": "generic_key"}`)},
+	}
+	for _, event := range failures {
+		_, err := h.decodeFailedEvent(event)
+		if err == nil {
+			t.Errorf(messageNotExpect, flowFailedEvent, event.Key, "error", err)
+		}
+	}
+}
+
+func TestFailedEvents(t *testing.T) {
+	payload := []byte(`{"cycle_key":"datadog_key"}`)
+	uc := &usecaseMock{
+		params: make(chan entity.ReplyInThread, 10),
+		events: map[string]entity.FailedEvent{
+			"event_1": {ID: "event_1", Key: "datadog_key", Vendor: vendorDatadog, Webhook: webhookDatadog, Payload: payload},
+			"event_2": {ID: "event_2", Vendor: "unknown", Webhook: "unknown", Payload: payload},
+		},
+	}
+	h := New(uc, queue.New(1, 1), Options{})
+
+	rec := httptest.NewRecorder()
+	h.ListFailedEvents(rec, httptest.NewRequest(http.MethodGet, "/admin/failed-events", nil))
+	events := []entity.FailedEvent{}
+	json.Unmarshal(rec.Body.Bytes(), &events)
+	if rec.Code != http.StatusOK || len(events) != 2 {
+		t.Errorf(messageNotExpect, flowFailedEvent, "list", 2, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetFailedEvent(rec, newFailedEventRequest(http.MethodGet, "event_1"))
+	event := entity.FailedEvent{}
+	json.Unmarshal(rec.Body.Bytes(), &event)
+	if rec.Code != http.StatusOK || event.ID != "event_1" || string(event.Payload) != string(payload) {
+		t.Errorf(messageNotExpect, flowFailedEvent, "get", "event_1", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetFailedEvent(rec, newFailedEventRequest(http.MethodGet, "event_3"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf(messageNotExpect, flowFailedEvent, "get unknown", http.StatusNotFound, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ReplayFailedEvent(rec, newFailedEventRequest(http.MethodPost, "event_1"))
+	if rec.Code != http.StatusOK {
+		t.Errorf(messageNotExpect, flowFailedEvent, "replay", http.StatusOK, rec.Code)
+	} else if param := uc.receive(t, 1)[0]; param.GetKey() != "datadog_key" {
+		t.Errorf(messageNotExpect, flowFailedEvent, "replay", "datadog_key", param.GetKey())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ReplayFailedEvent(rec, newFailedEventRequest(http.MethodPost, "event_2"))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf(messageNotExpect, flowFailedEvent, "replay unknown vendor", http.StatusUnprocessableEntity, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.DiscardFailedEvent(rec, newFailedEventRequest(http.MethodDelete, "event_2"))
+	if _, ok := uc.events["event_2"]; rec.Code != http.StatusNoContent || ok {
+		t.Errorf(messageNotExpect, flowFailedEvent, "discard", http.StatusNoContent, rec.Code)
+	}
+
+	uc.err = errorUsecase
+	rec = httptest.NewRecorder()
+	h.ReplayFailedEvent(rec, newFailedEventRequest(http.MethodPost, "event_1"))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf(messageNotExpect, flowFailedEvent, "usecase error", http.StatusInternalServerError, rec.Code)
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	"github.com/go-chi/chi"
+	log "github.com/sirupsen/logrus"
+)
+
+// GenericConfig defines Go template expression of each ReplyInThread field evaluated against the incoming JSON payload
+type GenericConfig struct {
+	Vendor    string            `json:"vendor"`
+	Key       string            `json:"key"`
+	Title     string            `json:"title"`
+	Summary   string            `json:"summary"`
+	Detail    string            `json:"detail"`
+	Status    string            `json:"status"`
+	Image     string            `json:"image"`
+	Channel   string            `json:"channel"`
+	Tags      string            `json:"tags"`
+	StatusMap map[string]string `json:"status_map"`
+}
+
+// GenericMapping is compiled GenericConfig used to parse generic JSON payload
+type GenericMapping struct {
+	vendor    string
+	fields    map[string]*template.Template
+	statusMap map[string]string
+}
+
+// NewGenericMapping will compile every template expression inside config and return the mapping used by generic webhook
+func NewGenericMapping(name string, config GenericConfig) (*GenericMapping, error) {
+	if config.Key == "" {
+		return nil, fmt.Errorf("Key expression of generic webhook %s is required", name)
+	}
+
+	expressions := map[string]string{
+		"key":     config.Key,
+		"title":   config.Title,
+		"summary": config.Summary,
+		"detail":  config.Detail,
+		"status":  config.Status,
+		"image":   config.Image,
+		"channel": config.Channel,
+		"tags":    config.Tags,
+	}
+
+	fields := map[string]*template.Template{}
+	for field, expression := range expressions {
+		if expression == "" {
+			continue
+		}
+
+		tmpl, err := template.New(field).Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s expression of generic webhook %s because %s", field, name, err)
+		}
+		fields[field] = tmpl
+	}
+
+	statusMap := map[string]string{}
+	for value, code := range config.StatusMap {
+		if _, ok := entity.ParseStatus(code); !ok {
+			return nil, fmt.Errorf("Unknown status %s for value %s of generic webhook %s", code, value, name)
+		}
+		statusMap[strings.ToLower(value)] = code
+	}
+
+	vendor := config.Vendor
+	if vendor == "" {
+		vendor = name
+	}
+
+	return &GenericMapping{
+		vendor:    vendor,
+		fields:    fields,
+		statusMap: statusMap,
+	}, nil
+}
+
+// Parse will evaluate every field expression against the payload and return the ReplyInThread object
+func (m *GenericMapping) Parse(payload interface{}) (GenericReplyThread, error) {
+	values := map[string]string{}
+	for field, tmpl := range m.fields {
+		buffer := bytes.Buffer{}
+		err := tmpl.Execute(&buffer, payload)
+		if err != nil {
+			return GenericReplyThread{}, fmt.Errorf("Failed to evaluate %s expression because %s", field, err)
+		}
+		values[field] = strings.TrimSpace(strings.Replace(buffer.String(), "<no value>", "", -1))
+	}
+
+	if values["key"] == "" {
+		return GenericReplyThread{}, fmt.Errorf("Key expression is evaluated into empty string")
+	}
+
+	status := values["status"]
+	if code, ok := m.statusMap[strings.ToLower(status)]; ok {
+		status = code
+	}
+
+	return GenericReplyThread{
+		Key:     values["key"],
+		Title:   values["title"],
+		Summary: values["summary"],
+		Detail:  values["detail"],
+		Status:  status,
+		Image:   values["image"],
+		Channel: values["channel"],
+		Tags:    values["tags"],
+		Vendor:  m.vendor,
+	}, nil
+}
+
+// GenericReplyThread is object contains every ReplyInThread field evaluated from generic JSON payload
+type GenericReplyThread struct {
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Detail  string `json:"detail"`
+	Status  string `json:"status"`
+	Image   string `json:"image"`
+	Channel string `json:"channel"`
+	Tags    string `json:"tags"`
+	Vendor  string
+
+	entity.Source `json:"-"`
+}
+
+// GetKey will return the incident unique id as identifier whether the incident should go to same thread or not
+func (r GenericReplyThread) GetKey() string {
+	return r.Key
+}
+
+// GetChannel will return specified Notification Channel ID evaluated from the payload
+func (r GenericReplyThread) GetChannel() string {
+	return r.Channel
+}
+
+// GetTags will return every tag evaluated from the payload as comma separated string
+func (r GenericReplyThread) GetTags() []string {
+	return splitTags(r.Tags)
+}
+
+// GetVendor will return the configured vendor name of the generic webhook
+func (r GenericReplyThread) GetVendor() string {
+	return r.Vendor
+}
+
+// GetTitle will return title string for notification message. In Slack Notification, this title will be visible in the slack notification pop-up
+func (r GenericReplyThread) GetTitle() string {
+	return r.Title
+}
+
+// GetStatus will return status of the incident. Unknown status is treated as triggered
+func (r GenericReplyThread) GetStatus() entity.IncidentStatus {
+	status, ok := entity.ParseStatus(r.Status)
+	if !ok {
+		return entity.StatusTriggered
+	}
+
+	return status
+}
+
+// GetSummary will return the summary evaluated from the payload which the default summary template shows below vendor and status
+func (r GenericReplyThread) GetSummary() string {
+	return r.Summary
+}
+
+// GetDetail will return detail string for notification thread. This message will be shown in the threads and can contain more detail incident data
+func (r GenericReplyThread) GetDetail() string {
+	return r.Detail
+}
+
+// GetImage will return the image url evaluated from the payload
+func (r GenericReplyThread) GetImage() string {
+	return r.Image
+}
+
+// GenericReplyInThread receive callback request from any tool and parse the request into ReplyInThread interface based on the configured mapping and call the usecase function
+func (s *Handler) GenericReplyInThread(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	_, ok := s.options.Generic[name]
+	if !ok {
+		log.Errorf("Generic webhook %s is not configured", name)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.receive(w, r, webhookGeneric+name)
+}
+
+// decodeGeneric will parse raw request of generic webhook into ReplyInThread object using the mapping of the name
+func (s *Handler) decodeGeneric(name string, source entity.Source, query url.Values) ([]entity.ReplyInThread, error) {
+	mapping, ok := s.options.Generic[name]
+	if !ok {
+		return nil, fmt.Errorf("Generic webhook %s is not configured", name)
+	}
+
+	// Keep number as it is so numeric identifier is not rendered in exponent format
+	var payload interface{}
+	decoder := json.NewDecoder(bytes.NewReader(source.Payload))
+	decoder.UseNumber()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal body because %s", err)
+	}
+
+	request, err := mapping.Parse(payload)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to map body of generic webhook %s because %s", name, err)
+	}
+	request.Source = source
+	if request.Channel == "" {
+		request.Channel = query.Get("channel")
+	}
+
+	return []entity.ReplyInThread{request}, nil
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+
+	"github.com/go-chi/chi"
+)
+
+var flowGenericMapping = "generic mapping flow"
+var flowGenericReply = "generic reply in thread flow"
+
+var genericConfig = GenericConfig{
+	Vendor:    "CI",
+	Key:       "{{.pipeline.id}}",
+	Title:     "*{{.pipeline.name}} {{.result}}*",
+	Detail:    "{{range .jobs}}{{.}} {{end}}",
+	Status:    "{{.result}}",
+	Image:     "{{.image}}",
+	Channel:   "{{.channel}}",
+	StatusMap: map[string]string{"passed": "recover", "Failed": "error"},
+}
+
+func TestNewGenericMapping(t *testing.T) {
+	configs := []GenericConfig{
+		genericConfig,
+		{Title: "title"},
+		{Key: "{{.key"},
+		{Key: "{{.key}}", StatusMap: map[string]string{"ok": "unknown"}},
+	}
+	expected := []bool{true, false, false, false}
+
+	for k, config := range configs {
+		_, err := NewGenericMapping("ci", config)
+		if (err == nil) != expected[k] {
+			t.Errorf(messageNotExpect, flowGenericMapping, config.Key, expected[k], err)
+		}
+	}
+}
+
+func TestGenericReplyInThread(t *testing.T) {
+	mapping, err := NewGenericMapping("ci", genericConfig)
+	if err != nil {
+		t.Fatalf(messageNotExpect, flowGenericMapping, "ci", nil, err)
+	}
+
+	uc := &usecaseMock{params: make(chan entity.ReplyInThread, 10)}
+	h := New(uc, queue.New(1, 10), Options{Generic: map[string]*GenericMapping{"ci": mapping}})
+
+	payloads := []string{
+		`{"pipeline": {"id": 42, "name": "deploy"}, "result": "failed", "jobs": ["build", "test"], "channel": "C_BODY"}`,
+		`{"pipeline": {"id": 42, "name": "deploy"}, "result": "PASSED"}`,
+		`{"pipeline": {"id": 42, "name": "deploy"}, "result": "cancelled"}`,
+	}
+	expected := []entity.IncidentStatus{entity.StatusTriggered, entity.StatusRecovered, entity.StatusTriggered}
+
+	for k, payload := range payloads {
+		rec := httptest.NewRecorder()
+		h.GenericReplyInThread(rec, newGenericRequest("ci", "C_QUERY", payload))
+		if rec.Code != http.StatusOK {
+			t.Fatalf(messageNotExpect, flowGenericReply, payload, http.StatusOK, rec.Code)
+		}
+
+		param := uc.receive(t, 1)[0]
+		if param.GetKey() != "42" {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "42", param.GetKey())
+		}
+		if param.GetStatus() != expected[k] {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, expected[k], param.GetStatus())
+		}
+		if param.GetVendor() != "CI" {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "CI", param.GetVendor())
+		}
+		if param.GetImage() != "" {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "", param.GetImage())
+		}
+		if k == 0 && (param.GetChannel() != "C_BODY" || param.GetDetail() != "build test" || param.GetTitle() != "*deploy failed*") {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "C_BODY/build test/*deploy failed*", param)
+		}
+		if source := param.(entity.Sourced).GetSource(); source.Webhook != "generic/ci" || string(source.Payload) != payload || source.Query != "channel=C_QUERY" {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "raw request", source)
+		}
+		if k == 1 && param.GetChannel() != "C_QUERY" {
+			t.Errorf(messageNotExpect, flowGenericReply, payload, "C_QUERY", param.GetChannel())
+		}
+	}
+
+	requests := []*http.Request{
+		newGenericRequest("unknown", "", `{}`),
+		newGenericRequest("ci", "", `{`),
+		newGenericRequest("ci", "", `{"result": "failed"}`),
+	}
+	codes := []int{http.StatusNotFound, http.StatusBadRequest, http.StatusBadRequest}
+	for k, req := range requests {
+		rec := httptest.NewRecorder()
+		h.GenericReplyInThread(rec, req)
+		if rec.Code != codes[k] {
+			t.Errorf(messageNotExpect, flowGenericReply, req.URL.Path, codes[k], rec.Code)
+		}
+	}
+}
+
+func newGenericRequest(name, channel, payload string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook/generic/"+name+"/reply-in-thread?channel="+channel, strings.NewReader(payload))
+
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add("name", name)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
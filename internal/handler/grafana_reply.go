@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// GrafanaWebhook is object to cater Grafana unified alerting request parameter
+type GrafanaWebhook struct {
+	Receiver    string               `json:"receiver"`
+	Status      string               `json:"status"`
+	Alerts      []GrafanaReplyThread `json:"alerts"`
+	ExternalURL string               `json:"externalURL"`
+	Title       string               `json:"title"`
+	Message     string               `json:"message"`
+}
+
+// GrafanaReplyThread is object to cater single alert inside Grafana request parameter
+type GrafanaReplyThread struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+	SilenceURL   string            `json:"silenceURL"`
+	DashboardURL string            `json:"dashboardURL"`
+	PanelURL     string            `json:"panelURL"`
+	ImageURL     string            `json:"imageURL"`
+	ValueString  string            `json:"valueString"`
+	Vendor       string
+	Channel      string `json:"channel"`
+
+	entity.Source `json:"-"`
+}
+
+// GetKey will return the alert fingerprint as identifier whether the incident should go to same thread or not
+func (r GrafanaReplyThread) GetKey() string {
+	return r.Fingerprint
+}
+
+// GetChannel will return specified Notification Channel ID from Grafana Object
+func (r GrafanaReplyThread) GetChannel() string {
+	return r.Channel
+}
+
+// GetTags will return every label of the alert as "name:value" tag
+func (r GrafanaReplyThread) GetTags() []string {
+	return labelTags(r.Labels)
+}
+
+// GetVendor will return from which vendor this parameter is which is always Grafana in this case
+func (r GrafanaReplyThread) GetVendor() string {
+	return r.Vendor
+}
+
+// GetTitle will return title string for notification message. In Slack Notification, this title will be visible in the slack notification pop-up
+func (r GrafanaReplyThread) GetTitle() string {
+	title := r.Labels["alertname"]
+	if title == "" {
+		title = r.Annotations["summary"]
+	}
+	if r.GeneratorURL == "" {
+		return fmt.Sprintf("*%s*", escapeComparison(title))
+	}
+
+	return fmt.Sprintf("*<%s|%s>*", r.GeneratorURL, escapeComparison(title))
+}
+
+// GetStatus will return status of the incident. Firing alert with warning severity label is treated as warning
+func (r GrafanaReplyThread) GetStatus() entity.IncidentStatus {
+	if r.Status == "resolved" {
+		return entity.StatusRecovered
+	} else if strings.EqualFold(r.Labels["severity"], "warning") {
+		return entity.StatusWarning
+	}
+
+	return entity.StatusTriggered
+}
+
+// GetSummary will return at-glance facts specific to the vendor which the default summary template shows below vendor and status. Grafana has none
+func (r GrafanaReplyThread) GetSummary() string {
+	return ""
+}
+
+// GetDetail will return detail string for notification thread. This message will be shown in the threads and can contain more detail incident data
+func (r GrafanaReplyThread) GetDetail() string {
+	lines := []string{}
+	if summary := r.Annotations["summary"]; summary != "" {
+		lines = append(lines, fmt.Sprintf("*%s*", summary))
+	}
+	if description := r.Annotations["description"]; description != "" {
+		lines = append(lines, description)
+	}
+	if r.ValueString != "" {
+		lines = append(lines, fmt.Sprintf("Values: `%s`", r.ValueString))
+	}
+
+	links := []string{}
+	if r.PanelURL != "" {
+		links = append(links, fmt.Sprintf("<%s|Panel>", r.PanelURL))
+	}
+	if r.DashboardURL != "" {
+		links = append(links, fmt.Sprintf("<%s|Dashboard>", r.DashboardURL))
+	}
+	if r.SilenceURL != "" {
+		links = append(links, fmt.Sprintf("<%s|Silence>", r.SilenceURL))
+	}
+	if len(links) > 0 {
+		lines = append(lines, strings.Join(links, " | "))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GetImage will return the image url of the panel screenshot attached by Grafana
+func (r GrafanaReplyThread) GetImage() string {
+	return r.ImageURL
+}
+
+// GrafanaReplyInThread receive callback request from Grafana and parse every alert inside the request into ReplyInThread interface and call the usecase function
+func (s *Handler) GrafanaReplyInThread(w http.ResponseWriter, r *http.Request) {
+	s.receive(w, r, webhookGrafana)
+}
+
+// decodeGrafana will parse raw request of Grafana into ReplyInThread object of every alert. Every alert keeps the whole request as its raw request
+func (s *Handler) decodeGrafana(source entity.Source, query url.Values) ([]entity.ReplyInThread, error) {
+	request := GrafanaWebhook{}
+	err := json.Unmarshal(source.Payload, &request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal body because %s", err)
+	}
+
+	// Grafana payload has no channel field, so it is taken from the alert label or the webhook URL
+	channel := query.Get("channel")
+	params := make([]entity.ReplyInThread, 0, len(request.Alerts))
+	for _, alert := range request.Alerts {
+		alert.Vendor = vendorGrafana
+		alert.Source = source
+		alert.Channel = alert.Labels["channel"]
+		if alert.Channel == "" {
+			alert.Channel = channel
+		}
+
+		params = append(params, alert)
+	}
+
+	return params, nil
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+)
+
+var flowGrafanaReply = "grafana reply in thread flow"
+
+var grafanaPayload = `{
+	"receiver": "alert-thread",
+	"status": "firing",
+	"alerts": [
+		{
+			"status": "firing",
+			"labels": {"alertname": "CPU > 90%", "severity": "warning"},
+			"annotations": {"summary": "CPU is high", "description": "CPU of host-1 is above 90%"},
+			"generatorURL": "http://grafana/alerting/1/edit",
+			"fingerprint": "fp-1",
+			"panelURL": "http://grafana/d/1?viewPanel=2",
+			"imageURL": "http://grafana/image.png",
+			"valueString": "[ var='B' value=93 ]"
+		},
+		{
+			"status": "resolved",
+			"labels": {"alertname": "Disk", "channel": "C_LABEL"},
+			"fingerprint": "fp-2"
+		}
+	]
+}`
+
+func TestGrafanaReplyInThread(t *testing.T) {
+	uc := &usecaseMock{params: make(chan entity.ReplyInThread, 10)}
+	h := New(uc, queue.New(1, 10), Options{})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook/grafana/reply-in-thread?channel=C_QUERY", strings.NewReader(grafanaPayload))
+	rec := httptest.NewRecorder()
+	h.GrafanaReplyInThread(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(messageNotExpect, flowGrafanaReply, "status code", http.StatusOK, rec.Code)
+	}
+
+	params := map[string]entity.ReplyInThread{}
+	for _, param := range uc.receive(t, 2) {
+		params[param.GetKey()] = param
+	}
+
+	firing := params["fp-1"]
+	if firing == nil {
+		t.Fatalf(messageNotExpect, flowGrafanaReply, "fp-1", "alert", nil)
+	}
+	if firing.GetStatus() != entity.StatusWarning {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-1 status", entity.StatusWarning, firing.GetStatus())
+	}
+	if firing.GetChannel() != "C_QUERY" {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-1 channel", "C_QUERY", firing.GetChannel())
+	}
+	if firing.GetTitle() != "*<http://grafana/alerting/1/edit|CPU more than 90%>*" {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-1 title", "*<http://grafana/alerting/1/edit|CPU more than 90%>*", firing.GetTitle())
+	}
+	if firing.GetImage() != "http://grafana/image.png" {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-1 image", "http://grafana/image.png", firing.GetImage())
+	}
+	if !strings.Contains(firing.GetDetail(), "<http://grafana/d/1?viewPanel=2|Panel>") {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-1 detail", "panel link", firing.GetDetail())
+	}
+
+	resolved := params["fp-2"]
+	if resolved == nil {
+		t.Fatalf(messageNotExpect, flowGrafanaReply, "fp-2", "alert", nil)
+	}
+	if resolved.GetStatus() != entity.StatusRecovered {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-2 status", entity.StatusRecovered, resolved.GetStatus())
+	}
+	if resolved.GetChannel() != "C_LABEL" {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "fp-2 channel", "C_LABEL", resolved.GetChannel())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/webhook/grafana/reply-in-thread", strings.NewReader("{"))
+	rec = httptest.NewRecorder()
+	h.GrafanaReplyInThread(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf(messageNotExpect, flowGrafanaReply, "invalid body", http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Vendor name of every supported webhook
+const (
+	vendorDatadog      = "Datadog"
+	vendorGrafana      = "Grafana"
+	vendorNewRelic     = "New Relic"
+	vendorAlertmanager = "Alertmanager"
+)
+
+// Webhook name of every supported webhook kept inside raw request so it can be decoded again on replay. Generic webhook is followed by its mapping name
+const (
+	webhookDatadog      = "datadog"
+	webhookGrafana      = "grafana"
+	webhookNewRelic     = "newrelic"
+	webhookAlertmanager = "alertmanager"
+	webhookGeneric      = "generic/"
+)
+
+// comparisonReplacer turns comparison operators into words as Slack treat < and > as link delimiter
+var comparisonReplacer = strings.NewReplacer(
+	">=", "more/equal than",
+	">", "more than",
+	"<=", "less/equal than",
+	"<", "less than",
+)
+
+// escapeComparison will replace comparison operator inside text with its words representation
+func escapeComparison(text string) string {
+	return comparisonReplacer.Replace(text)
+}
+
+// splitTags will return every non empty tag inside comma separated tags string
+func splitTags(tags string) []string {
+	result := []string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// labelTags will return every label as "name:value" tag sorted by its name
+func labelTags(labels map[string]string) []string {
+	tags := make([]string, 0, len(labels))
+	for name, value := range labels {
+		tags = append(tags, fmt.Sprintf("%s:%s", name, value))
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// decode will parse the raw request of the webhook into ReplyInThread objects. It is shared by the webhooks and the failed event replay
+func (s *Handler) decode(source entity.Source) ([]entity.ReplyInThread, error) {
+	query, err := url.ParseQuery(source.Query)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse query because %s", err)
+	}
+
+	switch source.Webhook {
+	case webhookDatadog:
+		return s.decodeDatadog(source)
+	case webhookGrafana:
+		return s.decodeGrafana(source, query)
+	case webhookNewRelic:
+		return s.decodeNewRelic(source, query)
+	case webhookAlertmanager:
+		return s.decodeAlertmanager(source, query)
+	}
+	if strings.HasPrefix(source.Webhook, webhookGeneric) {
+		return s.decodeGeneric(strings.TrimPrefix(source.Webhook, webhookGeneric), source, query)
+	}
+
+	return nil, fmt.Errorf("Unknown webhook %s", source.Webhook)
+}
+
+// receive will read and decode the request of the webhook then dispatch every ReplyInThread objects
+func (s *Handler) receive(w http.ResponseWriter, r *http.Request, webhook string) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Errorf("Failed to read body because %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	params, err := s.decode(entity.Source{Webhook: webhook, Query: r.URL.RawQuery, Payload: body})
+	if err != nil {
+		log.Errorf("Failed to decode request of webhook %s because %s", webhook, err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.dispatch(w, params...)
+}
+
+// dispatch will queue the usecase process of every param and respond Service Unavailable when the queue is full so the vendor can retry later
+func (s *Handler) dispatch(w http.ResponseWriter, params ...entity.ReplyInThread) {
+	err := s.queue.Enqueue(func(ctx context.Context) {
+		for _, param := range params {
+			s.usecase.ReplyInThread(ctx, param)
+		}
+	})
+	if err != nil {
+		log.Errorf("Failed to queue %d notifications because %s", len(params), err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeJSON will write the value as JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("Failed to marshal response because %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
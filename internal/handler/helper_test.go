@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+)
+
+var flowDispatch = "dispatch flow"
+
+type queueMock struct {
+	err error
+}
+
+func (q *queueMock) Enqueue(job queue.Job) error {
+	return q.err
+}
+
+func TestDispatch(t *testing.T) {
+	uc := &usecaseMock{params: make(chan entity.ReplyInThread, 10)}
+
+	queues := []Queue{&queueMock{}, &queueMock{err: queue.ErrQueueFull}, &queueMock{err: queue.ErrQueueClosed}}
+	expected := []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+
+	for k, q := range queues {
+		h := New(uc, q, Options{})
+		rec := httptest.NewRecorder()
+		h.dispatch(rec, DatadogReplyThread{Key: "key"})
+		if rec.Code != expected[k] {
+			t.Errorf(messageNotExpect, flowDispatch, k, expected[k], rec.Code)
+		}
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	params := []entity.ReplyInThread{
+		DatadogReplyThread{Tags: "service:payment, env:prod,,team:core "},
+		GrafanaReplyThread{Labels: map[string]string{"team": "core", "alertname": "CPU"}},
+		AlertmanagerReplyThread{CommonLabels: map[string]string{"severity": "critical"}},
+		NewRelicReplyThread{PolicyName: "Payment", Severity: "CRITICAL"},
+		GenericReplyThread{Tags: "job:backup"},
+		DatadogReplyThread{},
+	}
+	expected := [][]string{
+		{"service:payment", "env:prod", "team:core"},
+		{"alertname:CPU", "team:core"},
+		{"severity:critical"},
+		{"policy:Payment", "severity:critical"},
+		{"job:backup"},
+		{},
+	}
+
+	for k, param := range params {
+		tags := param.GetTags()
+		if !reflect.DeepEqual(tags, expected[k]) {
+			t.Errorf(messageNotExpect, "get tags flow", fmt.Sprintf("param %d", k), expected[k], tags)
+		}
+	}
+}
@@ -2,23 +2,59 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
 )
 
 // Usecase is interface of slack-alert flow to send notification in thread
 type Usecase interface {
 	ReplyInThread(ctx context.Context, param entity.ReplyInThread) error
+
+	ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error)
+	GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error)
+	ReplayFailedEvent(ctx context.Context, event entity.FailedEvent, param entity.ReplyInThread) error
+	DiscardFailedEvent(ctx context.Context, id string) error
+
+	AcknowledgeIncident(ctx context.Context, key, user string) error
+	ResolveIncident(ctx context.Context, key, user string) error
+	SnoozeIncident(ctx context.Context, key, user string, duration time.Duration) error
+	CloseIncident(ctx context.Context, key, user string) error
+
+	ListOpenIncidents(ctx context.Context) (map[string]entity.Incident, error)
+	GetIncident(ctx context.Context, key string) (entity.Incident, error)
+	ThreadLinks(ctx context.Context, threads []entity.Thread) []string
+	MuteIncidents(ctx context.Context, pattern, user string, duration time.Duration) (entity.Mute, error)
+	ListMutes(ctx context.Context) ([]entity.Mute, error)
+}
+
+// Queue is interface of job queue used to process the usecase outside of the request
+type Queue interface {
+	Enqueue(job queue.Job) error
+}
+
+// Options contains configurable behaviour of the handler endpoint
+type Options struct {
+	// AlertmanagerGroupBy is list of labels used as incident key instead of Alertmanager groupKey
+	AlertmanagerGroupBy []string
+
+	// Generic is list of generic webhook mapping identified by its name in the webhook URL
+	Generic map[string]*GenericMapping
 }
 
 // Handler contains all dependencies for handler endpoint
 type Handler struct {
 	usecase Usecase
+	queue   Queue
+	options Options
 }
 
 // New will return object contain the handlers served by this service
-func New(usecase Usecase) *Handler {
+func New(usecase Usecase, queue Queue, options Options) *Handler {
 	return &Handler{
 		usecase: usecase,
+		queue:   queue,
+		options: options,
 	}
 }
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// NewRelicReplyThread is object to cater New Relic request parameter
+type NewRelicReplyThread struct {
+	IncidentID        json.Number `json:"incident_id"`
+	CurrentState      string      `json:"current_state"`
+	ConditionName     string      `json:"condition_name"`
+	PolicyName        string      `json:"policy_name"`
+	Severity          string      `json:"severity"`
+	Details           string      `json:"details"`
+	Owner             string      `json:"owner"`
+	IncidentURL       string      `json:"incident_url"`
+	RunbookURL        string      `json:"runbook_url"`
+	ViolationChartURL string      `json:"violation_chart_url"`
+	Vendor            string
+	Channel           string `json:"channel"`
+
+	entity.Source `json:"-"`
+}
+
+// GetKey will return the incident unique id as identifier whether the incident should go to same thread or not
+func (r NewRelicReplyThread) GetKey() string {
+	return r.IncidentID.String()
+}
+
+// GetChannel will return specified Notification Channel ID from New Relic Object
+func (r NewRelicReplyThread) GetChannel() string {
+	return r.Channel
+}
+
+// GetTags will return the policy name and severity as tag as New Relic does not send any label
+func (r NewRelicReplyThread) GetTags() []string {
+	tags := []string{}
+	if r.PolicyName != "" {
+		tags = append(tags, fmt.Sprintf("policy:%s", r.PolicyName))
+	}
+	if r.Severity != "" {
+		tags = append(tags, fmt.Sprintf("severity:%s", strings.ToLower(r.Severity)))
+	}
+
+	return tags
+}
+
+// GetVendor will return from which vendor this parameter is which is always New Relic in this case
+func (r NewRelicReplyThread) GetVendor() string {
+	return r.Vendor
+}
+
+// GetTitle will return title string for notification message. In Slack Notification, this title will be visible in the slack notification pop-up
+func (r NewRelicReplyThread) GetTitle() string {
+	if r.IncidentURL == "" {
+		return fmt.Sprintf("*%s*", escapeComparison(r.ConditionName))
+	}
+
+	return fmt.Sprintf("*<%s|%s>*", r.IncidentURL, escapeComparison(r.ConditionName))
+}
+
+// GetStatus will return status of the incident. Closed incident is recovered and acknowledged incident keep its own status
+func (r NewRelicReplyThread) GetStatus() entity.IncidentStatus {
+	switch strings.ToLower(r.CurrentState) {
+	case "closed":
+		return entity.StatusRecovered
+	case "acknowledged":
+		return entity.StatusAcknowledged
+	}
+
+	if strings.EqualFold(r.Severity, "warning") {
+		return entity.StatusWarning
+	}
+
+	return entity.StatusTriggered
+}
+
+// GetSummary will return at-glance facts specific to the vendor which the default summary template shows below vendor and status, i.e. who acknowledged the incident
+func (r NewRelicReplyThread) GetSummary() string {
+	if r.GetStatus() == entity.StatusAcknowledged && r.Owner != "" {
+		return fmt.Sprintf("Acknowledged By: *%s*", r.Owner)
+	}
+
+	return ""
+}
+
+// GetDetail will return detail string for notification thread. This message will be shown in the threads and can contain more detail incident data
+func (r NewRelicReplyThread) GetDetail() string {
+	lines := []string{}
+	if r.Details != "" {
+		lines = append(lines, r.Details)
+	}
+	if r.PolicyName != "" {
+		lines = append(lines, fmt.Sprintf("Policy: *%s*", r.PolicyName))
+	}
+	if r.RunbookURL != "" {
+		lines = append(lines, fmt.Sprintf("<%s|Runbook>", r.RunbookURL))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// GetImage will return the image url of violation chart
+func (r NewRelicReplyThread) GetImage() string {
+	return r.ViolationChartURL
+}
+
+// NewRelicReplyInThread receive callback request from New Relic and parse the request into ReplyInThread interface and call the usecase function
+func (s *Handler) NewRelicReplyInThread(w http.ResponseWriter, r *http.Request) {
+	s.receive(w, r, webhookNewRelic)
+}
+
+// decodeNewRelic will parse raw request of New Relic into ReplyInThread object
+func (s *Handler) decodeNewRelic(source entity.Source, query url.Values) ([]entity.ReplyInThread, error) {
+	request := NewRelicReplyThread{}
+	err := json.Unmarshal(source.Payload, &request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal body because %s", err)
+	}
+	request.Vendor = vendorNewRelic
+	request.Source = source
+	if request.Channel == "" {
+		request.Channel = query.Get("channel")
+	}
+
+	return []entity.ReplyInThread{request}, nil
+}
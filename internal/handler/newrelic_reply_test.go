@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+)
+
+var flowNewRelicReply = "new relic reply in thread flow"
+
+func TestNewRelicReplyInThread(t *testing.T) {
+	uc := &usecaseMock{params: make(chan entity.ReplyInThread, 10)}
+	h := New(uc, queue.New(1, 10), Options{})
+
+	payloads := []string{
+		`{"incident_id": 123, "current_state": "open", "severity": "CRITICAL", "condition_name": "Error rate > 5%", "incident_url": "http://nr/123", "violation_chart_url": "http://nr/chart.png", "channel": "C_BODY"}`,
+		`{"incident_id": 123, "current_state": "acknowledged", "owner": "John Doe", "condition_name": "Error rate > 5%"}`,
+		`{"incident_id": "123", "current_state": "closed", "condition_name": "Error rate > 5%"}`,
+	}
+	expected := []entity.IncidentStatus{
+		entity.StatusTriggered,
+		entity.StatusAcknowledged,
+		entity.StatusRecovered,
+	}
+
+	for k, payload := range payloads {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/newrelic/reply-in-thread?channel=C_QUERY", strings.NewReader(payload))
+		rec := httptest.NewRecorder()
+		h.NewRelicReplyInThread(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf(messageNotExpect, flowNewRelicReply, payload, http.StatusOK, rec.Code)
+		}
+
+		param := uc.receive(t, 1)[0]
+		if param.GetKey() != "123" {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, "123", param.GetKey())
+		}
+		if param.GetStatus() != expected[k] {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, expected[k], param.GetStatus())
+		}
+		if k == 0 && param.GetChannel() != "C_BODY" {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, "C_BODY", param.GetChannel())
+		}
+		if k == 0 && param.GetTitle() != "*<http://nr/123|Error rate more than 5%>*" {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, "*<http://nr/123|Error rate more than 5%>*", param.GetTitle())
+		}
+		if k == 1 && !strings.Contains(param.GetSummary(), "Acknowledged By: *John Doe*") {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, "acknowledged owner in summary", param.GetSummary())
+		}
+		if k == 2 && param.GetChannel() != "C_QUERY" {
+			t.Errorf(messageNotExpect, flowNewRelicReply, payload, "C_QUERY", param.GetChannel())
+		}
+	}
+}
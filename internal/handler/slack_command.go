@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+	sl "github.com/slack-go/slack"
+)
+
+// maxListedIncidents is the maximum number of incidents shown by the list command so the response is not truncated by Slack
+const maxListedIncidents = 20
+
+// slackCommandUsage is the response of help and unknown command
+const slackCommandUsage = "Usage:\n" +
+	"`list` show open incidents and active mutes\n" +
+	"`show <key>` show detail of the incident\n" +
+	"`ack <key>` acknowledge the incident\n" +
+	"`close <key>` close the incident immediately\n" +
+	"`mute <pattern> <duration>` mute incidents which key is matched with the pattern, e.g. `mute db-* 2h`"
+
+// SlackCommands receive slash command to query and manage the open incidents. Response is only visible to the user who run the command
+func (s *Handler) SlackCommands(w http.ResponseWriter, r *http.Request) {
+	command, err := sl.SlashCommandParse(r)
+	if err != nil {
+		log.Errorf("Failed to parse slack command because %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user := fmt.Sprintf("<@%s>", command.UserID)
+	args := strings.Fields(command.Text)
+	if len(args) == 0 {
+		args = []string{"help"}
+	}
+
+	text := ""
+	switch strings.ToLower(args[0]) {
+	case "list":
+		text = s.listIncidentsText(r.Context())
+	case "show":
+		if len(args) != 2 {
+			text = "Usage: `show <key>`"
+			break
+		}
+		text = s.showIncidentText(r.Context(), args[1])
+	case "ack", "close":
+		if len(args) != 2 {
+			text = fmt.Sprintf("Usage: `%s <key>`", args[0])
+			break
+		}
+		text = s.enqueueCommand(strings.ToLower(args[0]), args[1], user)
+	case "mute":
+		if len(args) != 3 {
+			text = "Usage: `mute <pattern> <duration>`, e.g. `mute db-* 2h`"
+			break
+		}
+		text = s.muteIncidentsText(r.Context(), args[1], args[2], user)
+	default:
+		text = slackCommandUsage
+	}
+
+	writeJSON(w, http.StatusOK, sl.Msg{
+		ResponseType: sl.ResponseTypeEphemeral,
+		Text:         text,
+	})
+}
+
+// listIncidentsText will return the open incidents ordered by their last update and the active mutes
+func (s *Handler) listIncidentsText(ctx context.Context) string {
+	incidents, err := s.usecase.ListOpenIncidents(ctx)
+	if err != nil {
+		return fmt.Sprintf("Failed to list incidents because %s", err)
+	}
+
+	keys := make([]string, 0, len(incidents))
+	for key := range incidents {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return incidents[keys[i]].LastUpdate.After(incidents[keys[j]].LastUpdate)
+	})
+
+	lines := []string{fmt.Sprintf("*%d open incidents*", len(keys))}
+	for k, key := range keys {
+		if k == maxListedIncidents {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(keys)-maxListedIncidents))
+			break
+		}
+
+		incident := incidents[key]
+		lines = append(lines, fmt.Sprintf("• `%s` *%s* %s since %s %s", key, incident.Title, incident.Status.Message,
+			entity.FormatTime(incident.LastUpdate), strings.Join(s.usecase.ThreadLinks(ctx, incident.Threads), " ")))
+	}
+
+	mutes, err := s.usecase.ListMutes(ctx)
+	if err != nil {
+		log.Warnf("Failed to list mutes because %s", err)
+	}
+	if len(mutes) > 0 {
+		lines = append(lines, fmt.Sprintf("*%d active mutes*", len(mutes)))
+	}
+	for _, mute := range mutes {
+		lines = append(lines, fmt.Sprintf("• `%s` until %s by %s", mute.Pattern, entity.FormatTime(mute.Until), mute.CreatedBy))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// showIncidentText will return the latest state of the incident and links of its threads
+func (s *Handler) showIncidentText(ctx context.Context, key string) string {
+	incident, err := s.usecase.GetIncident(ctx, key)
+	if err != nil {
+		return fmt.Sprintf("Failed to get incident `%s` because %s", key, err)
+	}
+
+	lines := []string{
+		fmt.Sprintf("*%s* `%s`", incident.Title, key),
+		incident.Summary,
+		fmt.Sprintf("Status: *%s* updated at %s", incident.Status.Message, entity.FormatTime(incident.LastUpdate)),
+	}
+	if incident.Bridge.Link != "" {
+		lines = append(lines, fmt.Sprintf("%s Link: %s", incident.Bridge.Name, incident.Bridge.Link))
+	}
+	if incident.AcknowledgedBy != "" {
+		lines = append(lines, fmt.Sprintf("Acknowledged By: %s at %s", incident.AcknowledgedBy, entity.FormatTime(incident.AcknowledgedAt)))
+	}
+	if incident.ResolvedBy != "" {
+		lines = append(lines, fmt.Sprintf("Resolved By: %s at %s", incident.ResolvedBy, entity.FormatTime(incident.ResolvedAt)))
+	}
+	lines = append(lines, "Threads:")
+	lines = append(lines, s.usecase.ThreadLinks(ctx, incident.Threads)...)
+
+	return strings.Join(lines, "\n")
+}
+
+// enqueueCommand will process the incident command in the queue as updating every thread might take longer than Slack waits for the response
+func (s *Handler) enqueueCommand(command, key, user string) string {
+	err := s.queue.Enqueue(func(ctx context.Context) {
+		var err error
+		if command == "ack" {
+			err = s.usecase.AcknowledgeIncident(ctx, key, user)
+		} else {
+			err = s.usecase.CloseIncident(ctx, key, user)
+		}
+		if err != nil {
+			log.Errorf("Failed to %s incident %s because %s", command, key, err)
+		}
+	})
+	if err != nil {
+		log.Errorf("Failed to queue %s of incident %s because %s", command, key, err)
+		return fmt.Sprintf("Failed to %s incident `%s` because %s", command, key, err)
+	}
+
+	if command == "ack" {
+		return fmt.Sprintf("Acknowledging incident `%s`", key)
+	}
+	return fmt.Sprintf("Closing incident `%s`", key)
+}
+
+// muteIncidentsText will mute the incidents matched with the pattern for the duration written like 30m or 2h
+func (s *Handler) muteIncidentsText(ctx context.Context, pattern, value, user string) string {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Sprintf("Invalid duration `%s`, use format like `30m` or `2h`", value)
+	}
+
+	mute, err := s.usecase.MuteIncidents(ctx, pattern, user, duration)
+	if err != nil {
+		return fmt.Sprintf("Failed to mute `%s` because %s", pattern, err)
+	}
+
+	return fmt.Sprintf("Muted incidents matching `%s` until %s", mute.Pattern, entity.FormatTime(mute.Until))
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+
+	sl "github.com/slack-go/slack"
+)
+
+var flowSlackCommands = "slack commands flow"
+
+func newSlackCommandRequest(text string) *http.Request {
+	body := url.Values{"command": {"/alerts"}, "text": {text}, "user_id": {"U123"}}.Encode()
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSlackCommands(t *testing.T) {
+	now := time.Now()
+	uc := &usecaseMock{
+		actions: make(chan string, 10),
+		incidents: map[string]entity.Incident{
+			"db_key": {Title: "Database latency", Summary: "Latency is high", Status: entity.StatusTriggered, LastUpdate: now,
+				Threads: []entity.Thread{{Channel: "C1", ThreadID: "1.1"}, {Channel: "C2", ThreadID: "2.2"}}},
+			"cpu_key": {Title: "CPU usage", Status: entity.StatusWarning, LastUpdate: now.Add(-time.Hour), AcknowledgedBy: "<@U1>",
+				Threads: []entity.Thread{{Channel: "C1", ThreadID: "3.3"}}},
+		},
+	}
+	h := New(uc, queue.New(1, 10), Options{})
+
+	texts := []string{
+		"list",
+		"show db_key",
+		"show unknown_key",
+		"ack cpu_key",
+		"close db_key",
+		"mute db-* 2h",
+		"mute db-* tomorrow",
+		"",
+		"ack",
+	}
+	expected := [][]string{
+		{"*2 open incidents*\n• `db_key` *Database latency* Triggered", "https://slack.test/C1/1.1 https://slack.test/C2/2.2\n• `cpu_key`"},
+		{"*Database latency* `db_key`\nLatency is high\nStatus: *Triggered*", "Threads:\nhttps://slack.test/C1/1.1\nhttps://slack.test/C2/2.2"},
+		{"Failed to get incident `unknown_key`"},
+		{"Acknowledging incident `cpu_key`"},
+		{"Closing incident `db_key`"},
+		{"Muted incidents matching `db-*` until"},
+		{"Invalid duration `tomorrow`"},
+		{"Usage:\n`list`"},
+		{"Usage: `ack <key>`"},
+	}
+
+	for k, text := range texts {
+		rec := httptest.NewRecorder()
+		h.SlackCommands(rec, newSlackCommandRequest(text))
+		if rec.Code != http.StatusOK {
+			t.Errorf(messageNotExpect, flowSlackCommands, text, http.StatusOK, rec.Code)
+			continue
+		}
+
+		response := sl.Msg{}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if response.ResponseType != sl.ResponseTypeEphemeral {
+			t.Errorf(messageNotExpect, flowSlackCommands, text, sl.ResponseTypeEphemeral, response.ResponseType)
+		}
+		for _, part := range expected[k] {
+			if !strings.Contains(response.Text, part) {
+				t.Errorf(messageNotExpect, flowSlackCommands, fmt.Sprintf("%s response", text), part, response.Text)
+			}
+		}
+	}
+
+	actions := []string{}
+	for i := 0; i < 2; i++ {
+		select {
+		case action := <-uc.actions:
+			actions = append(actions, action)
+		case <-time.After(time.Second):
+			t.Fatalf("Expecting 2 actions, only got %d", len(actions))
+		}
+	}
+	if actions[0] != entity.ActionAcknowledge+" cpu_key <@U123>" || actions[1] != "close db_key <@U123>" {
+		t.Errorf(messageNotExpect, flowSlackCommands, "actions", "[ack close]", actions)
+	}
+
+	rec := httptest.NewRecorder()
+	h.SlackCommands(rec, newSlackCommandRequest("list"))
+	response := sl.Msg{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	if !strings.Contains(response.Text, "*1 active mutes*\n• `db-*` until") || !strings.Contains(response.Text, "by <@U123>") {
+		t.Errorf(messageNotExpect, flowSlackCommands, "list mutes", "1 active mutes", response.Text)
+	}
+}
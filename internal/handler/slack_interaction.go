@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+	sl "github.com/slack-go/slack"
+)
+
+// snoozeDuration is how long the incident is snoozed by the Snooze button
+const snoozeDuration = time.Hour
+
+// SlackInteractions receive button actions clicked on the main thread message and apply them to the incident. Actions are processed in the queue as Slack expects response within 3 seconds
+func (s *Handler) SlackInteractions(w http.ResponseWriter, r *http.Request) {
+	callback := sl.InteractionCallback{}
+	err := json.Unmarshal([]byte(r.FormValue("payload")), &callback)
+	if err != nil {
+		log.Errorf("Failed to unmarshal slack interaction because %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if callback.Type != sl.InteractionTypeBlockActions {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	user := fmt.Sprintf("<@%s>", callback.User.ID)
+	actions := callback.ActionCallback.BlockActions
+	err = s.queue.Enqueue(func(ctx context.Context) {
+		for _, action := range actions {
+			err := s.applyAction(ctx, action.ActionID, action.Value, user)
+			if err != nil {
+				log.Errorf("Failed to %s incident %s because %s", action.ActionID, action.Value, err)
+			}
+		}
+	})
+	if err != nil {
+		log.Errorf("Failed to queue %d slack actions because %s", len(actions), err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// applyAction will call the usecase of the incident action done by the user
+func (s *Handler) applyAction(ctx context.Context, action, key, user string) error {
+	switch action {
+	case entity.ActionAcknowledge:
+		return s.usecase.AcknowledgeIncident(ctx, key, user)
+	case entity.ActionResolve:
+		return s.usecase.ResolveIncident(ctx, key, user)
+	case entity.ActionSnooze:
+		return s.usecase.SnoozeIncident(ctx, key, user, snoozeDuration)
+	}
+
+	return fmt.Errorf("Unknown action %s", action)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/queue"
+)
+
+var flowSlackInteractions = "slack interactions flow"
+
+var slackInteractionPayload = `{
+	"type": "block_actions",
+	"user": {"id": "U123", "username": "oncall"},
+	"actions": [
+		{"action_id": "acknowledge", "block_id": "incident_actions", "value": "incident_1", "type": "button"},
+		{"action_id": "snooze", "block_id": "incident_actions", "value": "incident_2", "type": "button"},
+		{"action_id": "resolve", "block_id": "incident_actions", "value": "incident_3", "type": "button"}
+	]
+}`
+
+func newSlackInteractionRequest(payload string) *http.Request {
+	body := url.Values{"payload": {payload}}.Encode()
+	req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSlackInteractions(t *testing.T) {
+	uc := &usecaseMock{actions: make(chan string, 10)}
+	h := New(uc, queue.New(1, 10), Options{})
+
+	rec := httptest.NewRecorder()
+	h.SlackInteractions(rec, newSlackInteractionRequest(slackInteractionPayload))
+	if rec.Code != http.StatusOK {
+		t.Fatalf(messageNotExpect, flowSlackInteractions, "status code", http.StatusOK, rec.Code)
+	}
+
+	actions := []string{}
+	for i := 0; i < 3; i++ {
+		select {
+		case action := <-uc.actions:
+			actions = append(actions, action)
+		case <-time.After(time.Second):
+			t.Fatalf("Expecting 3 actions, only got %d", len(actions))
+		}
+	}
+	sort.Strings(actions)
+
+	expected := []string{
+		entity.ActionAcknowledge + " incident_1 <@U123>",
+		entity.ActionResolve + " incident_3 <@U123>",
+		entity.ActionSnooze + " incident_2 <@U123> 1h0m0s",
+	}
+	for k, action := range actions {
+		if action != expected[k] {
+			t.Errorf(messageNotExpect, flowSlackInteractions, "action", expected[k], action)
+		}
+	}
+
+	requests := []*http.Request{
+		newSlackInteractionRequest("{"),
+		newSlackInteractionRequest(`{"type": "view_closed"}`),
+	}
+	codes := []int{http.StatusBadRequest, http.StatusOK}
+	for k, req := range requests {
+		rec = httptest.NewRecorder()
+		h.SlackInteractions(rec, req)
+		if rec.Code != codes[k] {
+			t.Errorf(messageNotExpect, flowSlackInteractions, "invalid payload", codes[k], rec.Code)
+		}
+	}
+
+	h = New(uc, &queueMock{err: queue.ErrQueueFull}, Options{})
+	rec = httptest.NewRecorder()
+	h.SlackInteractions(rec, newSlackInteractionRequest(slackInteractionPayload))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf(messageNotExpect, flowSlackInteractions, "queue full", http.StatusServiceUnavailable, rec.Code)
+	}
+	if err := h.applyAction(context.Background(), "unknown", "incident_1", "<@U123>"); err == nil {
+		t.Errorf(messageNotExpect, flowSlackInteractions, "unknown action", "error", err)
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	sl "github.com/slack-go/slack"
+)
+
+// NewSlackVerifier will return middleware rejecting request which is not signed by Slack using the app signing secret
+func NewSlackVerifier(secret string) (func(http.Handler) http.Handler, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("Slack signing secret is required")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				log.Errorf("Failed to read body because %s", err.Error())
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			verifier, err := sl.NewSecretsVerifier(r.Header, secret)
+			if err == nil {
+				verifier.Write(body)
+				err = verifier.Ensure()
+			}
+			if err != nil {
+				log.Warnf("Rejected slack request from %s because %s", r.RemoteAddr, err)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var flowSlackVerifier = "slack verifier flow"
+
+var slackSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// signSlackRequest will sign the request the same way Slack sign its request
+func signSlackRequest(r *http.Request, secret, body string, timestamp time.Time) {
+	value := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("v0:%s:%s", value, body)))
+
+	r.Header.Set("X-Slack-Request-Timestamp", value)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestNewSlackVerifier(t *testing.T) {
+	_, err := NewSlackVerifier("")
+	if err == nil {
+		t.Errorf(messageNotExpect, flowSlackVerifier, "empty secret", "error", err)
+	}
+
+	verifier, err := NewSlackVerifier(slackSecret)
+	if err != nil {
+		t.Fatalf(messageNotExpect, flowSlackVerifier, "secret", nil, err)
+	}
+
+	body := "payload=%7B%7D"
+	next := verifier(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("payload") != "{}" {
+			t.Errorf(messageNotExpect, flowSlackVerifier, "body", "{}", r.FormValue("payload"))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	signs := []func(r *http.Request){
+		func(r *http.Request) { signSlackRequest(r, slackSecret, body, time.Now()) },
+		func(r *http.Request) { signSlackRequest(r, "wrong", body, time.Now()) },
+		func(r *http.Request) { signSlackRequest(r, slackSecret, body, time.Now().Add(-time.Hour)) },
+		func(r *http.Request) {},
+	}
+	expected := []int{http.StatusOK, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusUnauthorized}
+
+	for k, sign := range signs {
+		req := httptest.NewRequest(http.MethodPost, "/slack/interactions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		sign(req)
+
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, req)
+		if rec.Code != expected[k] {
+			t.Errorf(messageNotExpect, flowSlackVerifier, fmt.Sprintf("request %d", k), expected[k], rec.Code)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected \"%+v\", got \"%+v\""
+
+var errorUsecase = fmt.Errorf("timeout")
+
+type usecaseMock struct {
+	params    chan entity.ReplyInThread
+	actions   chan string
+	events    map[string]entity.FailedEvent
+	incidents map[string]entity.Incident
+	mutes     []entity.Mute
+	err       error
+}
+
+func (u *usecaseMock) ReplyInThread(ctx context.Context, param entity.ReplyInThread) error {
+	u.params <- param
+	return nil
+}
+
+func (u *usecaseMock) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	events := []entity.FailedEvent{}
+	for _, event := range u.events {
+		events = append(events, event)
+	}
+	return events, u.err
+}
+
+func (u *usecaseMock) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	return u.events[id], u.err
+}
+
+func (u *usecaseMock) ReplayFailedEvent(ctx context.Context, event entity.FailedEvent, param entity.ReplyInThread) error {
+	u.params <- param
+	return u.err
+}
+
+func (u *usecaseMock) DiscardFailedEvent(ctx context.Context, id string) error {
+	delete(u.events, id)
+	return u.err
+}
+
+func (u *usecaseMock) AcknowledgeIncident(ctx context.Context, key, user string) error {
+	u.actions <- fmt.Sprintf("%s %s %s", entity.ActionAcknowledge, key, user)
+	return u.err
+}
+
+func (u *usecaseMock) ResolveIncident(ctx context.Context, key, user string) error {
+	u.actions <- fmt.Sprintf("%s %s %s", entity.ActionResolve, key, user)
+	return u.err
+}
+
+func (u *usecaseMock) SnoozeIncident(ctx context.Context, key, user string, duration time.Duration) error {
+	u.actions <- fmt.Sprintf("%s %s %s %s", entity.ActionSnooze, key, user, duration)
+	return u.err
+}
+
+func (u *usecaseMock) CloseIncident(ctx context.Context, key, user string) error {
+	u.actions <- fmt.Sprintf("close %s %s", key, user)
+	return u.err
+}
+
+func (u *usecaseMock) ListOpenIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	return u.incidents, u.err
+}
+
+func (u *usecaseMock) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
+	incident, ok := u.incidents[key]
+	if !ok {
+		return entity.Incident{}, fmt.Errorf("Incident is not found")
+	}
+	return incident, u.err
+}
+
+func (u *usecaseMock) ThreadLinks(ctx context.Context, threads []entity.Thread) []string {
+	links := []string{}
+	for _, thread := range threads {
+		links = append(links, fmt.Sprintf("https://slack.test/%s/%s", thread.Channel, thread.ThreadID))
+	}
+	return links
+}
+
+func (u *usecaseMock) MuteIncidents(ctx context.Context, pattern, user string, duration time.Duration) (entity.Mute, error) {
+	mute := entity.Mute{Pattern: pattern, CreatedBy: user, Until: time.Now().Add(duration)}
+	u.mutes = append(u.mutes, mute)
+	return mute, u.err
+}
+
+func (u *usecaseMock) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	return u.mutes, u.err
+}
+
+func (u *usecaseMock) receive(t *testing.T, count int) []entity.ReplyInThread {
+	result := []entity.ReplyInThread{}
+	for i := 0; i < count; i++ {
+		select {
+		case param := <-u.params:
+			result = append(result, param)
+		case <-time.After(time.Second):
+			t.Fatalf("Expecting %d params, only got %d", count, len(result))
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Sweep is a cleanup run periodically by the janitor
+type Sweep func(ctx context.Context) error
+
+// Janitor runs the sweep periodically in background until it is shut down
+type Janitor struct {
+	interval time.Duration
+	sweep    Sweep
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New will return janitor running the sweep on every interval. The janitor is started immediately
+func New(interval time.Duration, sweep Sweep) *Janitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &Janitor{
+		interval: interval,
+		sweep:    sweep,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go j.run(ctx)
+
+	return j
+}
+
+func (j *Janitor) run(ctx context.Context) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := j.sweep(ctx)
+			if err != nil {
+				log.Errorf("Failed to run janitor sweep because %s", err)
+			}
+		}
+	}
+}
+
+// Shutdown will stop the janitor and wait until the running sweep is finished. The running sweep is cancelled immediately so it stops at its next check
+func (j *Janitor) Shutdown(ctx context.Context) error {
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
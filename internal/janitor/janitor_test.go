@@ -0,0 +1,52 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJanitor(t *testing.T) {
+	var runs int32
+	j := New(5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return fmt.Errorf("sweep error is only logged")
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	err := j.Shutdown(context.Background())
+	if err != nil {
+		t.Errorf("Shutdown is not expecting error %s", err)
+	}
+
+	stopped := atomic.LoadInt32(&runs)
+	if stopped < 2 {
+		t.Errorf("Janitor expecting to sweep periodically, got %d runs instead", stopped)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if runs := atomic.LoadInt32(&runs); runs != stopped {
+		t.Errorf("Janitor expecting to stop sweeping after shutdown, got %d more runs instead", runs-stopped)
+	}
+}
+
+func TestJanitorShutdownCancel(t *testing.T) {
+	started := make(chan struct{})
+	j := New(time.Millisecond, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-started
+
+	// Running sweep is cancelled so shutdown does not wait forever
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := j.Shutdown(ctx)
+	if err != nil {
+		t.Errorf("Shutdown expecting running sweep to be cancelled, got %s instead", err)
+	}
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned when every slot of the queue is already taken
+	ErrQueueFull = fmt.Errorf("Queue is full")
+
+	// ErrQueueClosed is returned when the queue is already shut down
+	ErrQueueClosed = fmt.Errorf("Queue is closed")
+)
+
+// Job is a unit of work processed by the queue worker
+type Job func(ctx context.Context)
+
+// Queue is bounded in-process job queue processed by fixed number of workers
+type Queue struct {
+	jobs   chan Job
+	wg     sync.WaitGroup
+	mutex  sync.RWMutex
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New will return queue with the given number of workers and maximum number of pending jobs. The workers are started immediately
+func New(workers, depth int) *Queue {
+	if workers <= 0 {
+		workers = 1
+	}
+	if depth < 0 {
+		depth = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		jobs:   make(chan Job, depth),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	q.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		job(q.ctx)
+	}
+}
+
+// Enqueue will put the job into the queue without waiting. It returns ErrQueueFull when there is no slot left
+func (q *Queue) Enqueue(job Job) error {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Shutdown will stop accepting new job and wait until every pending job is processed. When the context is done first, the running jobs are cancelled
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mutex.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected \"%+v\", got \"%+v\""
+
+var flowEnqueue = "enqueue flow"
+var flowShutdown = "shutdown flow"
+
+func TestEnqueue(t *testing.T) {
+	q := New(1, 1)
+
+	block := make(chan struct{})
+	started := make(chan struct{})
+	err := q.Enqueue(func(ctx context.Context) {
+		close(started)
+		<-block
+	})
+	if err != nil {
+		t.Errorf(messageNotExpect, flowEnqueue, "first job", nil, err)
+	}
+	<-started
+
+	// The only worker is busy so the next job wait in the only slot
+	err = q.Enqueue(func(ctx context.Context) {})
+	if err != nil {
+		t.Errorf(messageNotExpect, flowEnqueue, "second job", nil, err)
+	}
+
+	err = q.Enqueue(func(ctx context.Context) {})
+	if err != ErrQueueFull {
+		t.Errorf(messageNotExpect, flowEnqueue, "third job", ErrQueueFull, err)
+	}
+
+	close(block)
+	q.Shutdown(context.Background())
+
+	err = q.Enqueue(func(ctx context.Context) {})
+	if err != ErrQueueClosed {
+		t.Errorf(messageNotExpect, flowEnqueue, "closed queue", ErrQueueClosed, err)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	q := New(2, 100)
+
+	var processed int32
+	for i := 0; i < 50; i++ {
+		q.Enqueue(func(ctx context.Context) {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&processed, 1)
+		})
+	}
+
+	err := q.Shutdown(context.Background())
+	if err != nil {
+		t.Errorf(messageNotExpect, flowShutdown, "drain", nil, err)
+	}
+	if atomic.LoadInt32(&processed) != 50 {
+		t.Errorf(messageNotExpect, flowShutdown, "drain", 50, processed)
+	}
+
+	// Calling shutdown twice must be safe
+	err = q.Shutdown(context.Background())
+	if err != nil {
+		t.Errorf(messageNotExpect, flowShutdown, "second shutdown", nil, err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	q := New(1, 1)
+
+	cancelled := make(chan struct{})
+	q.Enqueue(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := q.Shutdown(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf(messageNotExpect, flowShutdown, "timeout", context.DeadlineExceeded, err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Errorf(messageNotExpect, flowShutdown, "timeout", "running job cancelled", "still running")
+	}
+}
@@ -0,0 +1,96 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowLink = "link flow"
+
+func TestNewBridge(t *testing.T) {
+	_, err := NewMeet(MeetOptions{})
+	if err == nil {
+		t.Errorf(messageNotExpect, "new meet flow", "empty prefix", "error", err)
+	}
+
+	_, err = NewZoom(ZoomOptions{})
+	if err == nil {
+		t.Errorf(messageNotExpect, "new zoom flow", "empty rooms", "error", err)
+	}
+
+	jitsi, err := NewJitsi(JitsiOptions{})
+	if err != nil {
+		t.Errorf(messageNotError, "new jitsi flow", "default server", err)
+	} else if jitsi.server != defaultJitsiServer {
+		t.Errorf(messageNotExpect, "new jitsi flow", "default server", defaultJitsiServer, jitsi.server)
+	}
+}
+
+func TestMeetLink(t *testing.T) {
+	ctx := context.Background()
+	meet, _ := NewMeet(MeetOptions{Prefix: "http://g.co/meet/team-"})
+	named, _ := NewMeet(MeetOptions{Prefix: "http://g.co/meet/team-", Name: "Meet"})
+
+	keys := []string{"123456", "cycle_key-1"}
+	expected := []entity.Bridge{
+		{Name: "Hangout", Link: "http://g.co/meet/team-123456"},
+		{Name: "Hangout", Link: "http://g.co/meet/team-cycle_key-1"},
+	}
+
+	for k, key := range keys {
+		result, err := meet.Link(ctx, key, nil)
+		if err != nil || result != expected[k] {
+			t.Errorf(messageNotExpect, flowLink, key, expected[k], result)
+		}
+	}
+
+	result, _ := named.Link(ctx, "123456", nil)
+	if result != (entity.Bridge{Name: "Meet", Link: "http://g.co/meet/team-123456"}) {
+		t.Errorf(messageNotExpect, flowLink, "configured name", "Meet", result)
+	}
+}
+
+func TestZoomLink(t *testing.T) {
+	ctx := context.Background()
+	zoom, _ := NewZoom(ZoomOptions{Rooms: map[string]string{"C_OPS": "https://zoom.us/j/1", "C_DBA": "https://zoom.us/j/2"}})
+	withDefault, _ := NewZoom(ZoomOptions{Rooms: map[string]string{"C_OPS": "https://zoom.us/j/1"}, Default: "https://zoom.us/j/9"})
+
+	objs := []*Zoom{zoom, zoom, zoom, withDefault}
+	channels := [][]string{{"C_DBA", "C_OPS"}, {"C_PAYMENT", "C_OPS"}, {"C_PAYMENT"}, {"C_PAYMENT"}}
+	expected := []entity.Bridge{
+		{Name: "Zoom", Link: "https://zoom.us/j/2"},
+		{Name: "Zoom", Link: "https://zoom.us/j/1"},
+		{},
+		{Name: "Zoom", Link: "https://zoom.us/j/9"},
+	}
+
+	for k, obj := range objs {
+		result, err := obj.Link(ctx, "key", channels[k])
+		if (err != nil) != (expected[k].Link == "") {
+			t.Errorf(messageNotExpect, flowLink, channels[k], expected[k], err)
+		}
+		if result != expected[k] {
+			t.Errorf(messageNotExpect, flowLink, channels[k], expected[k], result)
+		}
+	}
+}
+
+func TestJitsiLink(t *testing.T) {
+	ctx := context.Background()
+	jitsi, _ := NewJitsi(JitsiOptions{Server: "https://jitsi.example.com/", RoomPrefix: "incident-"})
+
+	result, err := jitsi.Link(ctx, "db/primary down", nil)
+	if err != nil {
+		t.Fatalf(messageNotError, flowLink, "jitsi room", err)
+	}
+
+	expected := entity.Bridge{Name: "Jitsi", Link: "https://jitsi.example.com/incident-db-primary-down"}
+	if result != expected {
+		t.Errorf(messageNotExpect, flowLink, "jitsi room", expected, result)
+	}
+}
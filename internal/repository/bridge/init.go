@@ -0,0 +1,28 @@
+package bridge
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// TypeMeet uses Google Meet link made of a prefix and the incident key
+	TypeMeet = "meet"
+
+	// TypeZoom uses static Zoom room of the incident channel
+	TypeZoom = "zoom"
+
+	// TypeJitsi uses Jitsi room named after the incident key
+	TypeJitsi = "jitsi"
+
+	// TypeNone disables the conference bridge
+	TypeNone = "none"
+)
+
+// invalidRoomChars is characters not safe to be used inside room name of the link
+var invalidRoomChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// roomName will return the incident key as room name safe to be used inside the link
+func roomName(key string) string {
+	return strings.Trim(invalidRoomChars.ReplaceAllString(key, "-"), "-")
+}
@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// defaultJitsiServer is the public Jitsi server used when no server is configured
+const defaultJitsiServer = "https://meet.jit.si"
+
+// JitsiOptions contains configuration of Jitsi bridge
+type JitsiOptions struct {
+	// Server is URL of the Jitsi server, e.g. https://jitsi.example.com
+	Server string
+
+	// RoomPrefix is written before the incident key so the room is not shared with other users of a public server
+	RoomPrefix string
+}
+
+// Jitsi is conference bridge using Jitsi room named after the incident key
+type Jitsi struct {
+	server     string
+	roomPrefix string
+}
+
+// NewJitsi will return conference bridge generating Jitsi room of the incident
+func NewJitsi(options JitsiOptions) (*Jitsi, error) {
+	if options.Server == "" {
+		options.Server = defaultJitsiServer
+	}
+
+	return &Jitsi{
+		server:     strings.TrimSuffix(options.Server, "/"),
+		roomPrefix: options.RoomPrefix,
+	}, nil
+}
+
+// Link will return Jitsi room of the incident
+func (j *Jitsi) Link(ctx context.Context, key string, channels []string) (entity.Bridge, error) {
+	room := roomName(j.roomPrefix + key)
+	if room == "" {
+		return entity.Bridge{}, fmt.Errorf("Failed to generate jitsi room because key %s has no valid character", key)
+	}
+
+	return entity.Bridge{Name: "Jitsi", Link: fmt.Sprintf("%s/%s", j.server, room)}, nil
+}
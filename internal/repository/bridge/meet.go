@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// defaultMeetName is the name shown beside Google Meet link, kept from the previous Hangout link
+const defaultMeetName = "Hangout"
+
+// MeetOptions contains configuration of Google Meet bridge
+type MeetOptions struct {
+	// Prefix is the link before the incident key, e.g. https://g.co/meet/team-
+	Prefix string
+
+	// Name is shown beside the link. Default is Hangout
+	Name string
+}
+
+// Meet is conference bridge using Google Meet link made of a prefix and the incident key
+type Meet struct {
+	prefix string
+	name   string
+}
+
+// NewMeet will return conference bridge generating Google Meet link of the incident
+func NewMeet(options MeetOptions) (*Meet, error) {
+	if options.Prefix == "" {
+		return nil, fmt.Errorf("Meet link prefix is not configured")
+	}
+
+	if options.Name == "" {
+		options.Name = defaultMeetName
+	}
+
+	return &Meet{
+		prefix: options.Prefix,
+		name:   options.Name,
+	}, nil
+}
+
+// Link will return Google Meet link of the incident. The key is used as it is so the link is the same as the one shared before
+func (m *Meet) Link(ctx context.Context, key string, channels []string) (entity.Bridge, error) {
+	return entity.Bridge{Name: m.name, Link: m.prefix + key}, nil
+}
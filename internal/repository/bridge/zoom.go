@@ -0,0 +1,49 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// ZoomOptions contains configuration of Zoom bridge
+type ZoomOptions struct {
+	// Rooms is link of the Zoom room owned by each channel
+	Rooms map[string]string
+
+	// Default is link of the Zoom room used when none of the incident channels has a room
+	Default string
+}
+
+// Zoom is conference bridge using static Zoom room of the incident channel
+type Zoom struct {
+	rooms       map[string]string
+	defaultRoom string
+}
+
+// NewZoom will return conference bridge picking Zoom room of the incident
+func NewZoom(options ZoomOptions) (*Zoom, error) {
+	if len(options.Rooms) == 0 && options.Default == "" {
+		return nil, fmt.Errorf("Zoom room is not configured")
+	}
+
+	return &Zoom{
+		rooms:       options.Rooms,
+		defaultRoom: options.Default,
+	}, nil
+}
+
+// Link will return Zoom room of the first incident channel having one, or the default room
+func (z *Zoom) Link(ctx context.Context, key string, channels []string) (entity.Bridge, error) {
+	for _, channel := range channels {
+		if room, ok := z.rooms[channel]; ok {
+			return entity.Bridge{Name: "Zoom", Link: room}, nil
+		}
+	}
+	if z.defaultRoom != "" {
+		return entity.Bridge{Name: "Zoom", Link: z.defaultRoom}, nil
+	}
+
+	return entity.Bridge{}, fmt.Errorf("No zoom room is configured for channels %v of incident %s", channels, key)
+}
@@ -0,0 +1,41 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// Separator separates the destination name and its channel, e.g. email:oncall
+const Separator = ":"
+
+// Notification is interface of notification channel used as a destination of the composite
+type Notification interface {
+	SendMessage(ctx context.Context, param entity.Notification) (string, error)
+	UpdateMessage(ctx context.Context, param entity.Notification) error
+}
+
+// Permalinker is optional interface of destination able to return link of a thread
+type Permalinker interface {
+	GetPermalink(ctx context.Context, thread entity.Thread) (string, error)
+}
+
+// Composite is notification channel delivering each channel to its destination. Channel is written as <destination>:<channel> so the incident
+// routed to several destinations has separate thread for every destination. Channel without known destination goes to the fallback destination
+type Composite struct {
+	destinations map[string]Notification
+	fallback     string
+}
+
+// NewNotification will return notification channel delivering to the destinations mapped by their names
+func NewNotification(destinations map[string]Notification, fallback string) (*Composite, error) {
+	if _, ok := destinations[fallback]; !ok {
+		return nil, fmt.Errorf("Fallback destination %s is not configured", fallback)
+	}
+
+	return &Composite{
+		destinations: destinations,
+		fallback:     fallback,
+	}, nil
+}
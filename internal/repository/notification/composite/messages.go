@@ -0,0 +1,60 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// SendMessage will send the message through the destination of the channel
+func (c *Composite) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	name, destination, channel := c.destination(param.Channel)
+	param.Channel = channel
+
+	threadID, err := destination.SendMessage(ctx, param)
+	if err != nil {
+		return threadID, fmt.Errorf("Failed to send message to %s because %s", name, err)
+	}
+
+	return threadID, nil
+}
+
+// UpdateMessage will update the thread message through the destination of the channel
+func (c *Composite) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	name, destination, channel := c.destination(param.Channel)
+	param.Channel = channel
+
+	err := destination.UpdateMessage(ctx, param)
+	if err != nil {
+		return fmt.Errorf("Failed to update message in %s because %s", name, err)
+	}
+
+	return nil
+}
+
+// GetPermalink will return link of the thread from the destination of its channel when it is supported
+func (c *Composite) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	name, destination, channel := c.destination(thread.Channel)
+	permalinker, ok := destination.(Permalinker)
+	if !ok {
+		return "", fmt.Errorf("Permalink is not supported by %s", name)
+	}
+
+	thread.Channel = channel
+	return permalinker.GetPermalink(ctx, thread)
+}
+
+// destination will return name and destination of the channel and the channel without destination prefix
+func (c *Composite) destination(channel string) (string, Notification, string) {
+	index := strings.Index(channel, Separator)
+	if index > 0 {
+		name := channel[:index]
+		if destination, ok := c.destinations[name]; ok {
+			return name, destination, channel[index+len(Separator):]
+		}
+	}
+
+	return c.fallback, c.destinations[c.fallback], channel
+}
@@ -0,0 +1,99 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var errorTimeout = fmt.Errorf("timeout exceeded")
+
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+
+type destinationMock struct {
+	name     string
+	channels []string
+}
+
+func (d *destinationMock) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	d.channels = append(d.channels, param.Channel)
+	if param.Channel == "broken" {
+		return "", errorTimeout
+	}
+	return fmt.Sprintf("%s-%s", d.name, param.Channel), nil
+}
+func (d *destinationMock) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	d.channels = append(d.channels, param.Channel)
+	if param.Channel == "broken" {
+		return errorTimeout
+	}
+	return nil
+}
+
+type permalinkMock struct {
+	destinationMock
+}
+
+func (p *permalinkMock) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	return fmt.Sprintf("https://%s/%s/%s", p.name, thread.Channel, thread.ThreadID), nil
+}
+
+func TestNewNotification(t *testing.T) {
+	_, err := NewNotification(map[string]Notification{"slack": &destinationMock{}}, "email")
+	if err == nil {
+		t.Errorf(messageNotExpect, "new notification flow", "unknown fallback", "error", err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	ctx := context.Background()
+	slack := &permalinkMock{destinationMock{name: "slack"}}
+	email := &destinationMock{name: "email"}
+	obj, _ := NewNotification(map[string]Notification{"slack": slack, "email": email}, "slack")
+
+	channels := []string{"slack:C1", "email:oncall", "C2", "https://teams/hook", "email:broken"}
+	expected := []string{"slack-C1", "email-oncall", "slack-C2", "slack-https://teams/hook", ""}
+	for k, channel := range channels {
+		threadID, err := obj.SendMessage(ctx, entity.Notification{Channel: channel})
+		if expected[k] == "" {
+			if err == nil || !strings.Contains(err.Error(), "Failed to send message to email because timeout") {
+				t.Errorf(messageNotExpect, flowSendMessage, channel, "destination error", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf(messageNotError, flowSendMessage, channel, err)
+		} else if threadID != expected[k] {
+			t.Errorf(messageNotExpect, flowSendMessage, channel, expected[k], threadID)
+		}
+	}
+
+	if strings.Join(slack.channels, ",") != "C1,C2,https://teams/hook" || strings.Join(email.channels, ",") != "oncall,broken" {
+		t.Errorf(messageNotExpect, flowSendMessage, "destinations", "channels without prefix", [][]string{slack.channels, email.channels})
+	}
+
+	err := obj.UpdateMessage(ctx, entity.Notification{Channel: "email:oncall", Metadata: map[string]string{"timestamp": "email-oncall"}})
+	if err != nil {
+		t.Errorf(messageNotError, flowUpdateMessage, "email:oncall", err)
+	}
+	err = obj.UpdateMessage(ctx, entity.Notification{Channel: "slack:broken"})
+	if err == nil || !strings.Contains(err.Error(), "slack") {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "slack:broken", "destination error", err)
+	}
+
+	link, err := obj.GetPermalink(ctx, entity.Thread{Channel: "slack:C1", ThreadID: "1.1"})
+	if err != nil || link != "https://slack/C1/1.1" {
+		t.Errorf(messageNotExpect, "get permalink flow", "slack:C1", "https://slack/C1/1.1", link)
+	}
+	_, err = obj.GetPermalink(ctx, entity.Thread{Channel: "email:oncall", ThreadID: "1.1"})
+	if err == nil {
+		t.Errorf(messageNotExpect, "get permalink flow", "email:oncall", "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"strconv"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/format"
+)
+
+// Discord limits of embed and thread name length
+const (
+	maxTitleLength       = 256
+	maxDescriptionLength = 4096
+	maxThreadNameLength  = 100
+)
+
+// message is Discord message containing the notification as embed
+type message struct {
+	Embeds []embed `json:"embeds"`
+}
+
+type embed struct {
+	Title       string      `json:"title,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Color       int         `json:"color"`
+	Image       *embedImage `json:"image,omitempty"`
+}
+
+type embedImage struct {
+	URL string `json:"url"`
+}
+
+// newMessage will return message with embed colored from the notification color containing its title, message and image. Title is plain text as Discord does not render link in it
+func newMessage(param entity.Notification) message {
+	color, err := strconv.ParseInt(param.GetColor(), 16, 32)
+	if err != nil {
+		color = 0
+	}
+
+	content := embed{
+		Title:       truncate(format.PlainText(param.Title), maxTitleLength),
+		Description: truncate(format.Markdown(param.Message), maxDescriptionLength),
+		Color:       int(color),
+	}
+	if param.Image != "" {
+		content.Image = &embedImage{URL: param.Image}
+	}
+
+	return message{Embeds: []embed{content}}
+}
+
+// threadName will return name of the incident thread as Discord does not render markdown in it
+func threadName(param entity.Notification) string {
+	name := param.Title
+	if name == "" {
+		name = param.Key
+	}
+	if name == "" {
+		name = "Incident"
+	}
+
+	return truncate(format.PlainText(name), maxThreadNameLength)
+}
+
+// truncate will cut the text into the maximum number of characters
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+
+	return string(runes[:max-1]) + "…"
+}
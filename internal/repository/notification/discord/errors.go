@@ -0,0 +1,39 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+)
+
+// newResponseError will return error of the response including the waiting time requested by Discord when it is rate limited.
+// Discord returns the waiting time in seconds either in Retry-After header or retry_after field of the body
+func newResponseError(response *http.Response, body []byte) *httperr.ResponseError {
+	err := httperr.New("Discord", response, body)
+	if response.StatusCode != http.StatusTooManyRequests {
+		return err
+	}
+
+	rateLimit := struct {
+		RetryAfter float64 `json:"retry_after"`
+	}{}
+	if json.Unmarshal(body, &rateLimit) == nil && rateLimit.RetryAfter > 0 {
+		err.RetryAfter = time.Duration(rateLimit.RetryAfter * float64(time.Second))
+	}
+
+	return err
+}
+
+// isNotFound will check whether Discord respond the request as the channel or message is not found, e.g. thread which is not started
+func isNotFound(err error) bool {
+	var responseErr *httperr.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// Retryable will check whether the request may succeed when it is sent to Discord again. Missing configuration is returned again on retry
+func Retryable(err error) bool {
+	return err != errorEmptyThreadID && err != errorEmptyGuildID && httperr.Retryable(err)
+}
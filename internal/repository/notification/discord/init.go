@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAPIURL        = "https://discord.com/api/v10"
+	defaultTimeout       = 10 * time.Second
+	defaultArchiveMinute = 1440
+)
+
+// Options defines how Discord notification is delivered
+type Options struct {
+	// Token is the bot token used to call Discord API. The bot must be able to send messages and create threads in the channels
+	Token string
+
+	// Forums lists forum channels. Incident in forum channel is created as forum post instead of thread started from parent message
+	Forums []string
+
+	// GuildID is the server of the channels, used to build link of the thread
+	GuildID string
+
+	// ArchiveMinutes is how long inactive thread is kept before it is archived by Discord. It must be 60, 1440, 4320 or 10080
+	ArchiveMinutes int
+
+	// APIURL replaces the Discord API endpoint, e.g. for testing against local stand-in
+	APIURL string
+
+	// Timeout is the maximum duration of each request to Discord
+	Timeout time.Duration
+}
+
+// Discord contains dependencies needed by Discord integration to send notification
+type Discord struct {
+	client  *http.Client
+	options Options
+	forums  map[string]bool
+}
+
+// NewNotification will return discord object used to do Discord integration
+func NewNotification(options Options) (*Discord, error) {
+	if options.Token == "" {
+		return nil, fmt.Errorf("Discord bot token is required")
+	}
+	if options.APIURL == "" {
+		options.APIURL = defaultAPIURL
+	}
+	if options.ArchiveMinutes <= 0 {
+		options.ArchiveMinutes = defaultArchiveMinute
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	options.APIURL = strings.TrimSuffix(options.APIURL, "/")
+
+	forums := map[string]bool{}
+	for _, channel := range options.Forums {
+		forums[channel] = true
+	}
+
+	return &Discord{
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
+		forums:  forums,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var errorEmptyThreadID = fmt.Errorf("ThreadID is required")
+var errorEmptyGuildID = fmt.Errorf("Guild id is required to build discord link")
+
+// SendMessage will send new embed to Discord. If thread_id is provided, the embed will go to the thread. Otherwise it will create a new thread from parent message,
+// or a new post when the channel is forum. Thread id is the same as id of its parent message in Discord, so the parent is never posted again
+// when only the thread is failed to be started. The thread is started again on the next reply instead
+func (d *Discord) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	threadID := param.Metadata["timestamp"]
+	if threadID != "" {
+		err := d.request(ctx, http.MethodPost, d.channelURL(threadID, "messages"), newMessage(param), nil)
+		if isNotFound(err) && !d.forums[param.Channel] {
+			err = d.startThread(ctx, param, threadID)
+			if err != nil {
+				return threadID, err
+			}
+			err = d.request(ctx, http.MethodPost, d.channelURL(threadID, "messages"), newMessage(param), nil)
+		}
+		return threadID, err
+	}
+
+	result := struct {
+		ID string `json:"id"`
+	}{}
+	if d.forums[param.Channel] {
+		post := map[string]interface{}{
+			"name":                  threadName(param),
+			"auto_archive_duration": d.options.ArchiveMinutes,
+			"message":               newMessage(param),
+		}
+		err := d.request(ctx, http.MethodPost, d.channelURL(param.Channel, "threads"), post, &result)
+		if err != nil {
+			return "", err
+		}
+
+		return result.ID, nil
+	}
+
+	err := d.request(ctx, http.MethodPost, d.channelURL(param.Channel, "messages"), newMessage(param), &result)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.startThread(ctx, param, result.ID)
+	if err != nil {
+		log.Warnf("Thread of message %s is started on the next reply. %s", result.ID, err)
+	}
+
+	return result.ID, nil
+}
+
+// startThread will start the incident thread from its parent message
+func (d *Discord) startThread(ctx context.Context, param entity.Notification, messageID string) error {
+	thread := map[string]interface{}{
+		"name":                  threadName(param),
+		"auto_archive_duration": d.options.ArchiveMinutes,
+	}
+	err := d.request(ctx, http.MethodPost, d.channelURL(param.Channel, "messages", messageID, "threads"), thread, nil)
+	if err != nil {
+		return fmt.Errorf("Failed to start thread from message %s because %s", messageID, err)
+	}
+
+	return nil
+}
+
+// UpdateMessage will edit the embed of thread parent message provided. Parent of forum post is the first message inside the post
+func (d *Discord) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	threadID, ok := param.Metadata["timestamp"]
+	if !ok || threadID == "" {
+		return errorEmptyThreadID
+	}
+
+	channel := param.Channel
+	if d.forums[param.Channel] {
+		channel = threadID
+	}
+
+	return d.request(ctx, http.MethodPatch, d.channelURL(channel, "messages", threadID), newMessage(param), nil)
+}
+
+// GetPermalink will return link of the incident thread. Guild id must be configured to build the link
+func (d *Discord) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	if thread.ThreadID == "" {
+		return "", errorEmptyThreadID
+	}
+	if d.options.GuildID == "" {
+		return "", errorEmptyGuildID
+	}
+
+	return fmt.Sprintf("https://discord.com/channels/%s/%s", d.options.GuildID, thread.ThreadID), nil
+}
+
+// channelURL will return Discord API URL of the channel followed by the path elements
+func (d *Discord) channelURL(channel string, elements ...string) string {
+	endpoint := fmt.Sprintf("%s/channels/%s", d.options.APIURL, url.PathEscape(channel))
+	for _, element := range elements {
+		endpoint = fmt.Sprintf("%s/%s", endpoint, url.PathEscape(element))
+	}
+
+	return endpoint
+}
+
+// request will call Discord API with the bot token and decode the successful response into result when it is given
+func (d *Discord) request(ctx context.Context, method, endpoint string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bot "+d.options.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return newResponseError(response, body)
+	}
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body, result)
+}
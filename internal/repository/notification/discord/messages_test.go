@@ -0,0 +1,232 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+)
+
+var messageFailedObj = "Failed to create notification object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+
+// discordServer is local stand-in of Discord API recording every request it receives. Message of thread which is not started is not found
+type discordServer struct {
+	mutex          sync.Mutex
+	requests       []string
+	bodies         []string
+	threads        map[string]bool
+	threadFailures int
+}
+
+func (s *discordServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.Header.Get("Authorization")))
+	s.bodies = append(s.bodies, string(body))
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/channels/limited"):
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"message": "You are being rate limited.", "retry_after": 1.5, "global": false}`)
+	case strings.HasPrefix(r.URL.Path, "/api/channels/private"):
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "Missing Access", "code": 50001}`)
+	case strings.HasSuffix(r.URL.Path, "/threads") && s.threadFailures > 0:
+		s.threadFailures--
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message": "Internal Server Error", "code": 0}`)
+	case strings.HasSuffix(r.URL.Path, "/threads"):
+		if s.threads == nil {
+			s.threads = map[string]bool{}
+		}
+		// Thread started from message has the message id, while forum post is a new thread
+		thread := "900000000000000002"
+		if parts := strings.Split(r.URL.Path, "/"); parts[len(parts)-3] == "messages" {
+			thread = parts[len(parts)-2]
+		}
+		s.threads[thread] = true
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": "900000000000000002", "type": 11}`)
+	case strings.HasPrefix(r.URL.Path, "/api/channels/9") && r.Method == http.MethodPost && !s.threads[strings.Split(r.URL.Path, "/")[3]]:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Unknown Channel", "code": 10003}`)
+	default:
+		fmt.Fprint(w, `{"id": "900000000000000001", "channel_id": "800000000000000001"}`)
+	}
+}
+
+func newTestNotification(t *testing.T, server *httptest.Server) *Discord {
+	obj, err := NewNotification(Options{Token: "token_1", Forums: []string{"forum_1"}, GuildID: "guild_1", APIURL: server.URL + "/api"})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	return obj
+}
+
+func TestNewNotification(t *testing.T) {
+	_, err := NewNotification(Options{})
+	if err == nil {
+		t.Errorf(messageNotExpect, "new notification flow", "without token", "error", err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	stub := &discordServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newTestNotification(t, server)
+
+	params := []entity.Notification{
+		{Channel: "text_1", Title: "*CPU usage*", Message: "From: *Datadog*", Color: entity.StatusTriggered.Color},
+		{Channel: "forum_1", Title: "*CPU usage*", Color: entity.StatusWarning.Color},
+		{Channel: "text_1", Message: "detail", Image: "http://image.png", Metadata: map[string]string{"timestamp": "900000000000000001"}},
+	}
+	threadIDs := []string{"900000000000000001", "900000000000000002", "900000000000000001"}
+	for k, param := range params {
+		threadID, err := obj.SendMessage(ctx, param)
+		if err != nil {
+			t.Errorf(messageNotError, flowSendMessage, param.Channel, err)
+		} else if threadID != threadIDs[k] {
+			t.Errorf(messageNotExpect, flowSendMessage, param.Channel, threadIDs[k], threadID)
+		}
+	}
+
+	expected := []string{
+		"POST /api/channels/text_1/messages Bot token_1",
+		"POST /api/channels/text_1/messages/900000000000000001/threads Bot token_1",
+		"POST /api/channels/forum_1/threads Bot token_1",
+		"POST /api/channels/900000000000000001/messages Bot token_1",
+	}
+	if strings.Join(stub.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(messageNotExpect, flowSendMessage, "requests", expected, stub.requests)
+	}
+
+	bodies := []string{
+		`{"embeds":[{"title":"CPU usage","description":"From: **Datadog**","color":16711680}]}`,
+		`{"auto_archive_duration":1440,"name":"CPU usage"}`,
+		`{"auto_archive_duration":1440,"message":{"embeds":[{"title":"CPU usage","color":16751616}]},"name":"CPU usage"}`,
+		`{"embeds":[{"description":"detail","color":16777215,"image":{"url":"http://image.png"}}]}`,
+	}
+	for k, body := range bodies {
+		if stub.bodies[k] != body {
+			t.Errorf(messageNotExpect, flowSendMessage, fmt.Sprintf("body %d", k), body, stub.bodies[k])
+		}
+	}
+
+	_, err := obj.SendMessage(ctx, entity.Notification{Channel: "limited"})
+	if delay, ok := httperr.RetryAfter(err); !ok || delay != 1500*time.Millisecond || !Retryable(err) {
+		t.Errorf(messageNotExpect, flowSendMessage, "rate limited", 1500*time.Millisecond, err)
+	}
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "private"})
+	if _, ok := httperr.RetryAfter(err); err == nil || ok || Retryable(err) || !strings.Contains(err.Error(), "Missing Access") {
+		t.Errorf(messageNotExpect, flowSendMessage, "forbidden", "Missing Access", err)
+	}
+}
+
+func TestThreadName(t *testing.T) {
+	// Datadog title links to the monitor with Slack markup
+	param := entity.Notification{Key: "key_1", Title: "*<https://app.datadoghq.com/monitors#1|[Triggered] CPU usage>*"}
+	expected := "[Triggered] CPU usage (https://app.datadoghq.com/monitors#1)"
+
+	if name := threadName(param); name != expected {
+		t.Errorf(messageNotExpect, "thread name flow", "linked title", expected, name)
+	}
+	if title := newMessage(param).Embeds[0].Title; title != expected {
+		t.Errorf(messageNotExpect, "thread name flow", "embed title", expected, title)
+	}
+	if name := threadName(entity.Notification{Key: "key_1"}); name != "key_1" {
+		t.Errorf(messageNotExpect, "thread name flow", "without title", "key_1", name)
+	}
+}
+
+func TestSendMessageThreadFailure(t *testing.T) {
+	stub := &discordServer{threadFailures: 1}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newTestNotification(t, server)
+
+	// Parent is posted once even though its thread is failed to be started, so retry of the notification does not duplicate it
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Channel: "text_1", Title: "*CPU usage*"})
+	if err != nil || threadID != "900000000000000001" {
+		t.Errorf(messageNotExpect, flowSendMessage, "failed thread", "900000000000000001", err)
+	}
+
+	// Thread is started again by the next reply
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "text_1", Message: "detail", Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "reply of failed thread", err)
+	}
+
+	expected := []string{
+		"POST /api/channels/text_1/messages Bot token_1",
+		"POST /api/channels/text_1/messages/900000000000000001/threads Bot token_1",
+		"POST /api/channels/900000000000000001/messages Bot token_1",
+		"POST /api/channels/text_1/messages/900000000000000001/threads Bot token_1",
+		"POST /api/channels/900000000000000001/messages Bot token_1",
+	}
+	if strings.Join(stub.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(messageNotExpect, flowSendMessage, "requests", expected, stub.requests)
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	stub := &discordServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newTestNotification(t, server)
+
+	params := []entity.Notification{
+		{Channel: "text_1", Title: "title", Color: entity.StatusRecovered.Color, Metadata: map[string]string{"timestamp": "900000000000000001"}},
+		{Channel: "forum_1", Title: "title", Metadata: map[string]string{"timestamp": "900000000000000002"}},
+	}
+	for _, param := range params {
+		err := obj.UpdateMessage(ctx, param)
+		if err != nil {
+			t.Errorf(messageNotError, flowUpdateMessage, param.Channel, err)
+		}
+	}
+
+	expected := []string{
+		"PATCH /api/channels/text_1/messages/900000000000000001 Bot token_1",
+		"PATCH /api/channels/900000000000000002/messages/900000000000000002 Bot token_1",
+	}
+	if strings.Join(stub.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "requests", expected, stub.requests)
+	}
+	if !strings.Contains(stub.bodies[0], `"color":49029`) {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "text_1", "recovered color", stub.bodies[0])
+	}
+
+	err := obj.UpdateMessage(ctx, entity.Notification{Channel: "text_1"})
+	if err != errorEmptyThreadID {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "without thread", errorEmptyThreadID, err)
+	}
+
+	link, err := obj.GetPermalink(ctx, entity.Thread{Channel: "text_1", ThreadID: "900000000000000001"})
+	if err != nil || link != "https://discord.com/channels/guild_1/900000000000000001" {
+		t.Errorf(messageNotExpect, "get permalink flow", "text_1", "thread link", link)
+	}
+}
@@ -0,0 +1,82 @@
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+
+	// maxStatuses is how many threads have their last status kept. The oldest thread is forgotten first
+	maxStatuses = 10000
+)
+
+// Options defines SMTP server and recipients of email notification
+type Options struct {
+	// Host and Port of SMTP server. STARTTLS is used when the server supports it
+	Host string
+	Port int
+
+	// TLS connects to the server using implicit TLS, usually on port 465
+	TLS bool
+
+	// Username and Password authenticate to the server with PLAIN auth. Authentication is skipped when username is empty
+	Username string
+	Password string
+
+	// From is sender address of every email
+	From string
+
+	// Recipients maps notification channel to its email addresses. Unmapped channel is rejected so the payload can not choose who is emailed
+	Recipients map[string][]string
+
+	// Timeout is the maximum duration of each email delivery
+	Timeout time.Duration
+}
+
+// Email contains dependencies needed by SMTP integration to send notification
+type Email struct {
+	options Options
+	from    *mail.Address
+	domain  string
+
+	// statuses keeps the last status color of the latest threads so only status change is emailed on parent update.
+	// It is kept in memory so the status may be emailed again after restart or once the thread is forgotten
+	statusMutex sync.Mutex
+	statuses    map[string]string
+	statusOrder []string
+	maxStatuses int
+}
+
+// NewNotification will return email object used to do SMTP integration
+func NewNotification(options Options) (*Email, error) {
+	if options.Host == "" {
+		return nil, fmt.Errorf("SMTP host is required")
+	}
+	if options.Port <= 0 {
+		options.Port = 25
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	if len(options.Recipients) == 0 {
+		return nil, fmt.Errorf("Recipients of at least one channel are required")
+	}
+
+	from, err := mail.ParseAddress(options.From)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid sender address %s because %s", options.From, err)
+	}
+
+	return &Email{
+		options:     options,
+		from:        from,
+		domain:      from.Address[strings.LastIndex(from.Address, "@")+1:],
+		statuses:    map[string]string{},
+		maxStatuses: maxStatuses,
+	}, nil
+}
@@ -0,0 +1,238 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/format"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var errorEmptyThreadID = fmt.Errorf("ThreadID is required")
+var errorNoRecipient = fmt.Errorf("No recipient is configured for channel")
+
+// Retryable will check whether the email may be sent when it is sent again. Missing configuration is returned again on retry
+func Retryable(err error) bool {
+	return err != errorEmptyThreadID && !errors.Is(err, errorNoRecipient)
+}
+
+// SendMessage will send new email. If thread_id is provided, the email replies the thread using In-Reply-To and References headers.
+// Otherwise it will send the first email of the thread and return its Message-ID as thread id
+func (e *Email) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	threadID := param.Metadata["timestamp"]
+	messageID, err := e.send(ctx, param, threadID, param.Message)
+	if err != nil {
+		return threadID, err
+	}
+	if threadID != "" {
+		return threadID, nil
+	}
+
+	e.setStatus(messageID, param.GetColor())
+	return messageID, nil
+}
+
+// UpdateMessage will send status change email in the thread as sent email can not be edited. Nothing is sent when the status is not changed
+func (e *Email) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	threadID, ok := param.Metadata["timestamp"]
+	if !ok || threadID == "" {
+		return errorEmptyThreadID
+	}
+	if e.status(threadID) == param.GetColor() {
+		log.Debugf("Skipped status email of thread %s as its status is not changed", threadID)
+		return nil
+	}
+
+	_, err := e.send(ctx, param, threadID, fmt.Sprintf("Status Update:\n\n%s", param.Message))
+	if err != nil {
+		return err
+	}
+
+	e.setStatus(threadID, param.GetColor())
+	return nil
+}
+
+// send will deliver the email to recipients of the channel and return its Message-ID
+func (e *Email) send(ctx context.Context, param entity.Notification, threadID, message string) (string, error) {
+	recipients := e.recipients(param.Channel)
+	if len(recipients) == 0 {
+		return "", fmt.Errorf("%w %s", errorNoRecipient, param.Channel)
+	}
+
+	messageID := fmt.Sprintf("<%s@%s>", newMessageID(), e.domain)
+	content, err := e.compose(param, recipients, messageID, threadID, message)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.options.Timeout)
+	defer cancel()
+
+	err = e.deliver(ctx, recipients, content)
+	if err != nil {
+		return "", fmt.Errorf("Failed to send email because %s", err)
+	}
+
+	return messageID, nil
+}
+
+// compose will return the email headers and quoted-printable plain text body. Reply has the same subject prefixed with Re: so it is grouped by mail clients
+func (e *Email) compose(param entity.Notification, recipients []string, messageID, threadID, message string) ([]byte, error) {
+	subject := format.PlainText(param.Title)
+	if subject == "" {
+		subject = param.Key
+	}
+	if threadID != "" {
+		subject = "Re: " + subject
+	}
+
+	buffer := &bytes.Buffer{}
+	headers := [][2]string{
+		{"From", e.from.String()},
+		{"To", strings.Join(recipients, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+	}
+	if threadID != "" {
+		headers = append(headers, [2]string{"In-Reply-To", threadID}, [2]string{"References", threadID})
+	}
+	if param.Key != "" {
+		headers = append(headers, [2]string{"X-Incident-Key", mime.QEncoding.Encode("utf-8", param.Key)})
+	}
+	headers = append(headers,
+		[2]string{"MIME-Version", "1.0"},
+		[2]string{"Content-Type", `text/plain; charset="utf-8"`},
+		[2]string{"Content-Transfer-Encoding", "quoted-printable"},
+	)
+	for _, header := range headers {
+		fmt.Fprintf(buffer, "%s: %s\r\n", header[0], header[1])
+	}
+	buffer.WriteString("\r\n")
+
+	body := format.PlainText(message)
+	if param.Image != "" {
+		body = fmt.Sprintf("%s\n\nSnapshot: %s", body, param.Image)
+	}
+
+	writer := quotedprintable.NewWriter(buffer)
+	_, err := writer.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	if err != nil {
+		return nil, err
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// deliver will send the email content to the recipients through the SMTP server
+func (e *Email) deliver(ctx context.Context, recipients []string, content []byte) error {
+	address := net.JoinHostPort(e.options.Host, strconv.Itoa(e.options.Port))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if e.options.TLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: e.options.Host})
+	}
+
+	client, err := smtp.NewClient(conn, e.options.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && !e.options.TLS {
+		err = client.StartTLS(&tls.Config{ServerName: e.options.Host})
+		if err != nil {
+			return err
+		}
+	}
+	if e.options.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", e.options.Username, e.options.Password, e.options.Host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(e.from.Address)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(content)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// recipients will return configured email addresses of the channel
+func (e *Email) recipients(channel string) []string {
+	return e.options.Recipients[channel]
+}
+
+func (e *Email) status(threadID string) string {
+	e.statusMutex.Lock()
+	defer e.statusMutex.Unlock()
+
+	return e.statuses[threadID]
+}
+
+func (e *Email) setStatus(threadID, color string) {
+	e.statusMutex.Lock()
+	defer e.statusMutex.Unlock()
+
+	if _, ok := e.statuses[threadID]; !ok {
+		e.statusOrder = append(e.statusOrder, threadID)
+		if len(e.statusOrder) > e.maxStatuses {
+			delete(e.statuses, e.statusOrder[0])
+			e.statusOrder = e.statusOrder[1:]
+		}
+	}
+	e.statuses[threadID] = color
+}
+
+// newMessageID will return random local part of email Message-ID
+func newMessageID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,241 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageFailedObj = "Failed to create notification object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+
+// smtpServer is local stand-in of SMTP server recording recipients and content of every email it receives
+type smtpServer struct {
+	listener   net.Listener
+	mutex      sync.Mutex
+	recipients [][]string
+	messages   []*mail.Message
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	server := &smtpServer{listener: listener}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	fmt.Fprint(conn, "220 localhost ESMTP\r\n")
+
+	recipients := []string{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			fmt.Fprint(conn, "250 localhost\r\n")
+		case strings.HasPrefix(command, "RCPT TO:<REJECTED"):
+			fmt.Fprint(conn, "550 mailbox unavailable\r\n")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			recipients = append(recipients, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+			fmt.Fprint(conn, "250 OK\r\n")
+		case strings.HasPrefix(command, "DATA"):
+			fmt.Fprint(conn, "354 End data with <CR><LF>.<CR><LF>\r\n")
+			message, _ := mail.ReadMessage(readData(reader))
+			s.mutex.Lock()
+			s.recipients = append(s.recipients, recipients)
+			s.messages = append(s.messages, message)
+			s.mutex.Unlock()
+			fmt.Fprint(conn, "250 OK\r\n")
+		case strings.HasPrefix(command, "QUIT"):
+			fmt.Fprint(conn, "221 Bye\r\n")
+			return
+		default:
+			fmt.Fprint(conn, "250 OK\r\n")
+		}
+	}
+}
+
+// readData will read the email content until the line containing single dot
+func readData(reader *bufio.Reader) *strings.Reader {
+	content := strings.Builder{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil || line == ".\r\n" {
+			break
+		}
+		content.WriteString(strings.TrimPrefix(line, "."))
+	}
+
+	return strings.NewReader(content.String())
+}
+
+func newTestNotification(t *testing.T, server *smtpServer) *Email {
+	address := server.listener.Addr().(*net.TCPAddr)
+	obj, err := NewNotification(Options{
+		Host: "127.0.0.1",
+		Port: address.Port,
+		From: "Alert Thread <alert@example.com>",
+		Recipients: map[string][]string{
+			"oncall":   {"sre@example.com", "dev@example.com"},
+			"product":  {"qa@example.com", "pm@example.com"},
+			"rejected": {"rejected@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	return obj
+}
+
+func TestNewNotification(t *testing.T) {
+	recipients := map[string][]string{"oncall": {"sre@example.com"}}
+	options := []Options{
+		{Host: "localhost", From: "alert@example.com", Recipients: recipients},
+		{From: "alert@example.com", Recipients: recipients},
+		{Host: "localhost", From: "not an address", Recipients: recipients},
+		{Host: "localhost", From: "alert@example.com"},
+	}
+	expected := []bool{true, false, false, false}
+
+	for k, option := range options {
+		_, err := NewNotification(option)
+		if (err == nil) != expected[k] {
+			t.Errorf(messageNotExpect, "new notification flow", option.From, expected[k], err)
+		}
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	server := newSMTPServer(t)
+	defer server.listener.Close()
+
+	ctx := context.Background()
+	obj := newTestNotification(t, server)
+
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Key: "incident_1", Channel: "oncall", Title: "*CPU usage*", Message: "From: *Datadog*"})
+	if err != nil {
+		t.Fatalf(messageNotError, flowSendMessage, "parent", err)
+	}
+	if !strings.HasPrefix(threadID, "<") || !strings.HasSuffix(threadID, "@example.com>") {
+		t.Errorf(messageNotExpect, flowSendMessage, "parent", "Message-ID", threadID)
+	}
+
+	replyID, err := obj.SendMessage(ctx, entity.Notification{Key: "incident_1", Channel: "product", Title: "*CPU usage*", Message: "détail", Image: "http://image.png", Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Fatalf(messageNotError, flowSendMessage, "reply", err)
+	} else if replyID != threadID {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply", threadID, replyID)
+	}
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "rejected"})
+	if err == nil {
+		t.Errorf(messageNotExpect, flowSendMessage, "rejected", "error", err)
+	}
+
+	// Channel sent in the payload must not be used as the recipients
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "attacker@example.com"})
+	if err == nil || Retryable(err) {
+		t.Errorf(messageNotExpect, flowSendMessage, "unmapped", "error", err)
+	}
+
+	if len(server.messages) != 2 {
+		t.Fatalf(messageNotExpect, flowSendMessage, "messages", 2, len(server.messages))
+	}
+	parent, reply := server.messages[0].Header, server.messages[1].Header
+	if parent.Get("Message-ID") != threadID || parent.Get("Subject") != "CPU usage" || parent.Get("In-Reply-To") != "" || parent.Get("X-Incident-Key") != "incident_1" {
+		t.Errorf(messageNotExpect, flowSendMessage, "parent headers", threadID, parent)
+	}
+	if reply.Get("In-Reply-To") != threadID || reply.Get("References") != threadID || reply.Get("Subject") != "Re: CPU usage" || reply.Get("Message-ID") == threadID {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply headers", threadID, reply)
+	}
+	if strings.Join(server.recipients[0], ",") != "sre@example.com,dev@example.com" || strings.Join(server.recipients[1], ",") != "qa@example.com,pm@example.com" {
+		t.Errorf(messageNotExpect, flowSendMessage, "recipients", "mapped and listed recipients", server.recipients)
+	}
+
+	body := make([]byte, 1024)
+	n, _ := server.messages[1].Body.Read(body)
+	if !strings.Contains(string(body[:n]), "d=C3=A9tail") || !strings.Contains(string(body[:n]), "Snapshot: http://image.png") {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply body", "quoted-printable detail with snapshot", string(body[:n]))
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	server := newSMTPServer(t)
+	defer server.listener.Close()
+
+	ctx := context.Background()
+	obj := newTestNotification(t, server)
+
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Channel: "oncall", Title: "title", Color: entity.StatusTriggered.Color})
+	if err != nil {
+		t.Fatalf(messageNotError, flowUpdateMessage, "parent", err)
+	}
+
+	colors := []string{entity.StatusTriggered.Color, entity.StatusRecovered.Color, entity.StatusRecovered.Color}
+	for _, color := range colors {
+		err = obj.UpdateMessage(ctx, entity.Notification{Channel: "oncall", Title: "title", Message: "summary", Color: color, Metadata: map[string]string{"timestamp": threadID}})
+		if err != nil {
+			t.Errorf(messageNotError, flowUpdateMessage, color, err)
+		}
+	}
+
+	// Only the recovery is a status change after the parent email
+	if len(server.messages) != 2 {
+		t.Fatalf(messageNotExpect, flowUpdateMessage, "messages", 2, len(server.messages))
+	}
+	if update := server.messages[1].Header; update.Get("In-Reply-To") != threadID || update.Get("Subject") != "Re: title" {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "status email", threadID, update)
+	}
+
+	err = obj.UpdateMessage(ctx, entity.Notification{Channel: "oncall"})
+	if err != errorEmptyThreadID {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "without thread", errorEmptyThreadID, err)
+	}
+}
+
+func TestStatusBound(t *testing.T) {
+	obj, _ := NewNotification(Options{Host: "localhost", From: "alert@example.com", Recipients: map[string][]string{"oncall": {"sre@example.com"}}})
+	obj.maxStatuses = 2
+
+	for _, threadID := range []string{"thread_1", "thread_2", "thread_2", "thread_3"} {
+		obj.setStatus(threadID, entity.StatusTriggered.Color)
+	}
+
+	// The oldest thread is forgotten once the limit is reached
+	if len(obj.statuses) != 2 || obj.status("thread_1") != "" || obj.status("thread_3") != entity.StatusTriggered.Color {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "bounded statuses", "thread_2 and thread_3", obj.statuses)
+	}
+}
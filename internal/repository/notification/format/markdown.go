@@ -0,0 +1,25 @@
+package format
+
+import (
+	"regexp"
+)
+
+var (
+	slackLink = regexp.MustCompile(`<(https?://[^|>]+)\|([^>]+)>`)
+	slackURL  = regexp.MustCompile(`<(https?://[^|>]+)>`)
+	slackBold = regexp.MustCompile(`(^|[^*])\*([^*\s]|[^*\s][^*\n]*[^*\s])\*`)
+)
+
+// Markdown will convert Slack formatted text written by vendors and usecase into common markdown used by other notification channels
+func Markdown(text string) string {
+	text = slackLink.ReplaceAllString(text, "[$2]($1)")
+	text = slackURL.ReplaceAllString(text, "$1")
+	return slackBold.ReplaceAllString(text, "$1**$2**")
+}
+
+// PlainText will remove Slack formatting from the text for notification channels without markup, e.g. plain text email
+func PlainText(text string) string {
+	text = slackLink.ReplaceAllString(text, "$2 ($1)")
+	text = slackURL.ReplaceAllString(text, "$1")
+	return slackBold.ReplaceAllString(text, "$1$2")
+}
@@ -0,0 +1,45 @@
+package format
+
+import (
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	texts := []string{
+		"*Hangout Link* : http://g.co/meet/x\n\nFrom: *Datadog*",
+		"Still Triggered: *×2*",
+		"<https://app.datadoghq.com|Monitor> and <https://grafana.com>",
+		"**already bold** and 2 * 3 * 4",
+	}
+	expected := []string{
+		"**Hangout Link** : http://g.co/meet/x\n\nFrom: **Datadog**",
+		"Still Triggered: **×2**",
+		"[Monitor](https://app.datadoghq.com) and https://grafana.com",
+		"**already bold** and 2 * 3 * 4",
+	}
+
+	for k, text := range texts {
+		if result := Markdown(text); result != expected[k] {
+			t.Errorf("text %q expecting %q, got %q instead", text, expected[k], result)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	texts := []string{
+		"*Hangout Link* : http://g.co/meet/x\n\nFrom: *Datadog*",
+		"<https://app.datadoghq.com|Monitor> and <https://grafana.com>",
+		"2 * 3 * 4",
+	}
+	expected := []string{
+		"Hangout Link : http://g.co/meet/x\n\nFrom: Datadog",
+		"Monitor (https://app.datadoghq.com) and https://grafana.com",
+		"2 * 3 * 4",
+	}
+
+	for k, text := range texts {
+		if result := PlainText(text); result != expected[k] {
+			t.Errorf("text %q expecting %q, got %q instead", text, expected[k], result)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package httperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ResponseError is returned when the notification channel respond the request with non-successful status code
+type ResponseError struct {
+	Service    string
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s responded with status %d: %s", e.Service, e.StatusCode, e.Body)
+}
+
+// New will return error of the response of the service including the waiting time requested in Retry-After header
+func New(service string, response *http.Response, body []byte) *ResponseError {
+	err := &ResponseError{Service: service, StatusCode: response.StatusCode, Body: string(body)}
+	if seconds, parseErr := strconv.ParseFloat(response.Header.Get("Retry-After"), 64); parseErr == nil && seconds > 0 {
+		err.RetryAfter = time.Duration(seconds * float64(time.Second))
+	}
+
+	return err
+}
+
+// RetryAfter will return the waiting time requested by the notification channel when it is rate limited or unavailable
+func RetryAfter(err error) (time.Duration, bool) {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) && responseErr.RetryAfter > 0 {
+		return responseErr.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// Retryable will check whether the request may succeed when it is sent again. Client error other than timeout and rate limit,
+// e.g. unknown channel or invalid token, is returned again on retry
+func Retryable(err error) bool {
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) {
+		return true
+	}
+
+	code := responseErr.StatusCode
+	return code < http.StatusBadRequest || code >= http.StatusInternalServerError || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests
+}
@@ -0,0 +1,63 @@
+package httperr
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	headers := []string{"7", "1.5", "", "Wed, 21 Oct 2015 07:28:00 GMT", "-1"}
+	expected := []time.Duration{7 * time.Second, 1500 * time.Millisecond, 0, 0, 0}
+
+	for k, header := range headers {
+		response := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		response.Header.Set("Retry-After", header)
+
+		err := New("Teams", response, []byte("rate limited"))
+		if err.RetryAfter != expected[k] {
+			t.Errorf("Retry-After %q expecting %s, got %s instead", header, expected[k], err.RetryAfter)
+		}
+		if err.Error() != "Teams responded with status 429: rate limited" {
+			t.Errorf("Retry-After %q expecting service and status in error, got %s instead", header, err)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	errs := []error{
+		&ResponseError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second},
+		fmt.Errorf("Failed to send message because %w", &ResponseError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Second}),
+		&ResponseError{StatusCode: http.StatusForbidden},
+		fmt.Errorf("timeout"),
+	}
+	expected := []time.Duration{7 * time.Second, time.Second, 0, 0}
+
+	for k, err := range errs {
+		delay, ok := RetryAfter(err)
+		if delay != expected[k] || ok != (expected[k] > 0) {
+			t.Errorf("Error %s expecting %s, got %s (%t) instead", err, expected[k], delay, ok)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	errs := []error{
+		&ResponseError{StatusCode: http.StatusInternalServerError},
+		&ResponseError{StatusCode: http.StatusTooManyRequests},
+		&ResponseError{StatusCode: http.StatusRequestTimeout},
+		fmt.Errorf("Failed to send message because %w", &ResponseError{StatusCode: http.StatusNotFound}),
+		&ResponseError{StatusCode: http.StatusBadRequest},
+		&ResponseError{StatusCode: http.StatusUnauthorized},
+		&ResponseError{StatusCode: http.StatusForbidden},
+		fmt.Errorf("timeout"),
+	}
+	expected := []bool{true, true, true, false, false, false, false, true}
+
+	for k, err := range errs {
+		if Retryable(err) != expected[k] {
+			t.Errorf("Error %s expecting retryable %t, got %t instead", err, expected[k], !expected[k])
+		}
+	}
+}
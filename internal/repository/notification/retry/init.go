@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// Notification is interface of notification channel wrapped by the retry mechanism
+type Notification interface {
+	SendMessage(ctx context.Context, param entity.Notification) (string, error)
+	UpdateMessage(ctx context.Context, param entity.Notification) error
+}
+
+// Permalinker is optional interface of wrapped notification channel able to return link of a thread
+type Permalinker interface {
+	GetPermalink(ctx context.Context, thread entity.Thread) (string, error)
+}
+
+// Options defines how many times and how long the failed notification is retried
+type Options struct {
+	// MaxAttempts is the maximum number of attempts including the first one
+	MaxAttempts int
+
+	// BaseDelay is the waiting time before the first retry. The waiting time is doubled on every retry
+	BaseDelay time.Duration
+
+	// MaxDelay is the upper limit of waiting time between attempts
+	MaxDelay time.Duration
+
+	// RetryAfter extract waiting time requested by the notification channel from its error, e.g. rate limit response
+	RetryAfter func(err error) (time.Duration, bool)
+
+	// Retryable tells whether the failed notification may succeed when it is sent again, e.g. server error but not unknown channel.
+	// Every error is retried when it is not set
+	Retryable func(err error) bool
+}
+
+// Retry is notification channel which retry the failed notification with jittered exponential backoff
+type Retry struct {
+	notification Notification
+	options      Options
+}
+
+// permalinkRetry is retry of notification channel able to return link of a thread
+type permalinkRetry struct {
+	*Retry
+	permalinker Permalinker
+}
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = time.Second
+	defaultMaxDelay    = 30 * time.Second
+)
+
+// NewNotification will return notification channel wrapping the given notification with retry mechanism.
+// Link of a thread is only returned when the wrapped notification channel supports it
+func NewNotification(notification Notification, options Options) (Notification, error) {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultMaxAttempts
+	}
+	if options.BaseDelay <= 0 {
+		options.BaseDelay = defaultBaseDelay
+	}
+	if options.MaxDelay <= 0 {
+		options.MaxDelay = defaultMaxDelay
+	}
+
+	retry := &Retry{
+		notification: notification,
+		options:      options,
+	}
+	if permalinker, ok := notification.(Permalinker); ok {
+		return &permalinkRetry{Retry: retry, permalinker: permalinker}, nil
+	}
+
+	return retry, nil
+}
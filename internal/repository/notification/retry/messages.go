@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SendMessage will send the message through wrapped notification channel and retry it when failed
+func (r *Retry) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	threadID := ""
+	err := r.do(ctx, "send message", func() error {
+		var err error
+		threadID, err = r.notification.SendMessage(ctx, param)
+		return err
+	})
+
+	return threadID, err
+}
+
+// UpdateMessage will update the message through wrapped notification channel and retry it when failed
+func (r *Retry) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	return r.do(ctx, "update message", func() error {
+		return r.notification.UpdateMessage(ctx, param)
+	})
+}
+
+// GetPermalink will return link of the thread from wrapped notification channel and retry it when failed
+func (r *permalinkRetry) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	permalink := ""
+	err := r.do(ctx, "get permalink", func() error {
+		var err error
+		permalink, err = r.permalinker.GetPermalink(ctx, thread)
+		return err
+	})
+
+	return permalink, err
+}
+
+// do will call the operation until it succeed, the error is not retryable, the maximum attempt is reached or the context is done
+func (r *Retry) do(ctx context.Context, name string, operation func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = operation()
+		if err == nil || attempt >= r.options.MaxAttempts || (r.options.Retryable != nil && !r.options.Retryable(err)) {
+			return err
+		}
+
+		delay := r.delay(attempt, err)
+		log.Warnf("Failed to %s on attempt %d because %s. Retrying in %s", name, attempt, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// delay will return the waiting time requested by the notification channel or the jittered exponential backoff of the attempt.
+// Both are limited by MaxDelay so long rate limit does not stall the worker
+func (r *Retry) delay(attempt int, err error) time.Duration {
+	if r.options.RetryAfter != nil {
+		if delay, ok := r.options.RetryAfter(err); ok {
+			if delay > r.options.MaxDelay {
+				delay = r.options.MaxDelay
+			}
+			return delay
+		}
+	}
+
+	backoff := r.options.BaseDelay << uint(attempt-1)
+	if backoff <= 0 || backoff > r.options.MaxDelay {
+		backoff = r.options.MaxDelay
+	}
+
+	// Full jitter so replicas failing at the same time do not retry at the same time
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
+}
@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+
+var errorTimeout = fmt.Errorf("timeout exceeded")
+var errorNotFound = fmt.Errorf("channel not found")
+
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return "rate limited"
+}
+
+type notificationMock struct {
+	failures int
+	err      error
+	attempts int
+}
+
+func (n *notificationMock) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return "", n.err
+	}
+	return "thread_id", nil
+}
+
+func (n *notificationMock) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return n.err
+	}
+	return nil
+}
+
+func retryAfter(err error) (time.Duration, bool) {
+	if rateErr, ok := err.(*rateLimitError); ok {
+		return rateErr.retryAfter, true
+	}
+	return 0, false
+}
+
+func retryable(err error) bool {
+	return err != errorNotFound
+}
+
+func TestSendMessage(t *testing.T) {
+	ctx := context.Background()
+	options := Options{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, RetryAfter: retryAfter, Retryable: retryable}
+
+	mocks := []*notificationMock{
+		{failures: 0, err: errorTimeout},
+		{failures: 2, err: errorTimeout},
+		{failures: 3, err: errorTimeout},
+		{failures: 1, err: &rateLimitError{retryAfter: 2 * time.Millisecond}},
+		{failures: 1, err: &rateLimitError{retryAfter: time.Hour}},
+		{failures: 3, err: errorNotFound},
+	}
+	expectedErr := []error{nil, nil, errorTimeout, nil, nil, errorNotFound}
+	expectedAttempts := []int{1, 3, 3, 2, 2, 1}
+
+	for k, mock := range mocks {
+		obj, _ := NewNotification(mock, options)
+
+		start := time.Now()
+		threadID, err := obj.SendMessage(ctx, entity.Notification{})
+		if err != expectedErr[k] {
+			t.Errorf(messageNotExpect, flowSendMessage, k, expectedErr[k], err)
+		} else if err == nil && threadID != "thread_id" {
+			t.Errorf(messageNotExpect, flowSendMessage, k, "thread_id", threadID)
+		}
+		if mock.attempts != expectedAttempts[k] {
+			t.Errorf(messageNotExpect, flowSendMessage, k, expectedAttempts[k], mock.attempts)
+		}
+		if k == 3 && time.Since(start) < 2*time.Millisecond {
+			t.Errorf(messageNotExpect, flowSendMessage, "rate limit", "wait for retry after", time.Since(start))
+		}
+		// Long rate limit is limited by the maximum delay
+		if k == 4 && time.Since(start) > time.Second {
+			t.Errorf(messageNotExpect, flowSendMessage, "long rate limit", "wait for max delay", time.Since(start))
+		}
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	mock := &notificationMock{failures: 10, err: errorTimeout}
+	obj, _ := NewNotification(mock, Options{MaxAttempts: 5, BaseDelay: time.Hour})
+
+	// Cancelled context must stop waiting for the next attempt
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := obj.UpdateMessage(ctx, entity.Notification{})
+	if err != errorTimeout {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "cancelled", errorTimeout, err)
+	}
+	if mock.attempts != 1 {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "cancelled", 1, mock.attempts)
+	}
+
+	mock = &notificationMock{failures: 1, err: errorTimeout}
+	obj, _ = NewNotification(mock, Options{BaseDelay: time.Millisecond})
+	err = obj.UpdateMessage(context.Background(), entity.Notification{})
+	if err != nil || mock.attempts != 2 {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "retried", 2, mock.attempts)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	obj, _ := NewNotification(&notificationMock{}, Options{BaseDelay: time.Second, MaxDelay: 5 * time.Second})
+
+	for attempt := 1; attempt < 100; attempt++ {
+		delay := obj.(*Retry).delay(attempt, errorTimeout)
+		if delay <= 0 || delay > 5*time.Second {
+			t.Errorf(messageNotExpect, "delay flow", attempt, "between 0 and 5s", delay)
+		}
+	}
+}
+
+type permalinkMock struct {
+	notificationMock
+}
+
+func (n *permalinkMock) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return "", n.err
+	}
+	return "http://thread/" + thread.ThreadID, nil
+}
+
+func TestGetPermalink(t *testing.T) {
+	ctx := context.Background()
+	options := Options{BaseDelay: time.Millisecond}
+
+	// Notification channel which can not return the link is not wrapped as one which can
+	obj, _ := NewNotification(&notificationMock{}, options)
+	if _, ok := obj.(Permalinker); ok {
+		t.Errorf(messageNotExpect, "get permalink flow", "not supported", "no permalink", "permalink")
+	}
+
+	mock := &permalinkMock{notificationMock{failures: 1, err: errorTimeout}}
+	obj, _ = NewNotification(mock, options)
+	permalinker, ok := obj.(Permalinker)
+	if !ok {
+		t.Fatalf(messageNotExpect, "get permalink flow", "supported", "permalink", "no permalink")
+	}
+	link, err := permalinker.GetPermalink(ctx, entity.Thread{ThreadID: "thread_id"})
+	if err != nil || link != "http://thread/thread_id" || mock.attempts != 2 {
+		t.Errorf(messageNotExpect, "get permalink flow", "retried", "http://thread/thread_id", link)
+	}
+}
@@ -0,0 +1,35 @@
+package slack
+
+import (
+	"errors"
+	"time"
+
+	sl "github.com/slack-go/slack"
+)
+
+// RetryAfter will return the waiting time requested by Slack when the request is rate limited
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *sl.RateLimitedError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	return 0, false
+}
+
+// permanentErrors are errors of Slack API which are returned again on retry until the channel or the token is fixed
+var permanentErrors = map[string]bool{
+	"channel_not_found": true,
+	"not_in_channel":    true,
+	"is_archived":       true,
+	"invalid_auth":      true,
+	"not_authed":        true,
+	"account_inactive":  true,
+	"token_revoked":     true,
+	"missing_scope":     true,
+}
+
+// Retryable will check whether the request may succeed when it is sent to Slack again
+func Retryable(err error) bool {
+	return err != errorEmptyThreadID && !permanentErrors[err.Error()]
+}
@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var flowRetryAfter = "retry after flow"
+
+func TestRetryAfter(t *testing.T) {
+	ctx := context.Background()
+	obj, err := NewNotification(slackToken, Options{})
+	if err != nil {
+		t.Errorf(messageFailedObj, err)
+	}
+
+	gock.New(slackURL).
+		Post(sendMessageEndpoint).
+		Reply(429).
+		SetHeader("Retry-After", "7")
+	defer gock.Off()
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "channel_1"})
+	delay, ok := RetryAfter(err)
+	if !ok || delay != 7*time.Second {
+		t.Errorf(messageNotExpect, flowRetryAfter, "rate limited", 7*time.Second, delay)
+	}
+
+	_, ok = RetryAfter(errorTimeout)
+	if ok {
+		t.Errorf(messageNotExpect, flowRetryAfter, "timeout", false, ok)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	ctx := context.Background()
+	obj, err := NewNotification(slackToken, Options{})
+	if err != nil {
+		t.Errorf(messageFailedObj, err)
+	}
+
+	gockSendMessage()
+	defer gock.Off()
+
+	channels := []string{"channel_2", "channel_3"}
+	expected := []bool{true, false}
+	for k, channel := range channels {
+		_, err = obj.SendMessage(ctx, entity.Notification{Channel: channel})
+		if Retryable(err) != expected[k] {
+			t.Errorf(messageNotExpect, "retryable flow", channel, expected[k], err)
+		}
+	}
+
+	if Retryable(errorEmptyThreadID) {
+		t.Errorf(messageNotExpect, "retryable flow", "empty thread id", false, true)
+	}
+}
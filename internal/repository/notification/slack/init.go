@@ -4,14 +4,22 @@ import (
 	sl "github.com/slack-go/slack"
 )
 
+// Options defines optional behaviour of Slack notification
+type Options struct {
+	// Actions will add Acknowledge, Resolve and Snooze buttons into the main thread message. Slack interactivity must be configured to the service
+	Actions bool
+}
+
 // Slack contains dependencies needed by Slack integration to send notification
 type Slack struct {
-	client *sl.Client
+	client  *sl.Client
+	options Options
 }
 
 // NewNotification will return slack object used to do slack integration
-func NewNotification(token string) (*Slack, error) {
+func NewNotification(token string, options Options) (*Slack, error) {
 	return &Slack{
-		client: sl.New(token),
+		client:  sl.New(token),
+		options: options,
 	}, nil
 }
@@ -12,24 +12,15 @@ var errorEmptyThreadID = fmt.Errorf("ThreadID is required")
 
 // SendMessage will send new message to Slack. If thread_id is provided, the message will go to the thread. Otherwise it will create a new thread
 func (s *Slack) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
-	attachment := sl.Attachment{
-		Color: param.GetColor(),
-		Blocks: sl.Blocks{
-			BlockSet: []sl.Block{
-				sl.NewSectionBlock(sl.NewTextBlockObject(sl.MarkdownType, param.Message, false, false), nil, nil),
-			},
-		},
-	}
-	if param.Image != "" {
-		attachment.Blocks.BlockSet = append(attachment.Blocks.BlockSet, sl.NewImageBlock(param.Image, "alt text", "", nil))
-	}
+	threadID := param.Metadata["timestamp"]
+	attachment := s.attachment(param, threadID == "")
 
 	options := []sl.MsgOption{
 		sl.MsgOptionText(param.Title, false),
 		sl.MsgOptionAttachments(attachment),
 	}
-	if value, ok := param.Metadata["timestamp"]; ok && value != "" {
-		options = append(options, sl.MsgOptionTS(value))
+	if threadID != "" {
+		options = append(options, sl.MsgOptionTS(threadID))
 	}
 
 	_, threadID, err := s.client.PostMessageContext(ctx, param.Channel, options...)
@@ -42,17 +33,7 @@ func (s *Slack) SendMessage(ctx context.Context, param entity.Notification) (str
 
 // UpdateMessage will update the thread message provided
 func (s *Slack) UpdateMessage(ctx context.Context, param entity.Notification) error {
-	attachment := sl.Attachment{
-		Color: param.GetColor(),
-		Blocks: sl.Blocks{
-			BlockSet: []sl.Block{
-				sl.NewSectionBlock(sl.NewTextBlockObject(sl.MarkdownType, param.Message, false, false), nil, nil),
-			},
-		},
-	}
-	if param.Image != "" {
-		attachment.Blocks.BlockSet = append(attachment.Blocks.BlockSet, sl.NewImageBlock(param.Image, "alt text", "", nil))
-	}
+	attachment := s.attachment(param, true)
 
 	options := []sl.MsgOption{
 		sl.MsgOptionText(param.Title, true),
@@ -71,3 +52,34 @@ func (s *Slack) UpdateMessage(ctx context.Context, param entity.Notification) er
 
 	return nil
 }
+
+// attachment will return colored attachment containing the message, the image and the incident action buttons for main thread message
+// of incident which is not resolved yet
+func (s *Slack) attachment(param entity.Notification, parent bool) sl.Attachment {
+	attachment := sl.Attachment{
+		Color: param.GetColor(),
+		Blocks: sl.Blocks{
+			BlockSet: []sl.Block{
+				sl.NewSectionBlock(sl.NewTextBlockObject(sl.MarkdownType, param.Message, false, false), nil, nil),
+			},
+		},
+	}
+	if param.Image != "" {
+		attachment.Blocks.BlockSet = append(attachment.Blocks.BlockSet, sl.NewImageBlock(param.Image, "alt text", "", nil))
+	}
+	if s.options.Actions && parent && param.Key != "" && !param.Resolved {
+		attachment.Blocks.BlockSet = append(attachment.Blocks.BlockSet, actionBlock(param.Key))
+	}
+
+	return attachment
+}
+
+// actionBlock will return buttons to act on the incident. The incident key is sent as the button value
+func actionBlock(key string) *sl.ActionBlock {
+	acknowledge := sl.NewButtonBlockElement(entity.ActionAcknowledge, key, sl.NewTextBlockObject(sl.PlainTextType, "Acknowledge", false, false))
+	resolve := sl.NewButtonBlockElement(entity.ActionResolve, key, sl.NewTextBlockObject(sl.PlainTextType, "Resolve", false, false))
+	resolve.Style = sl.StylePrimary
+	snooze := sl.NewButtonBlockElement(entity.ActionSnooze, key, sl.NewTextBlockObject(sl.PlainTextType, "Snooze 1h", false, false))
+
+	return sl.NewActionBlock("incident_actions", acknowledge, resolve, snooze)
+}
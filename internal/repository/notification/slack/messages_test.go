@@ -66,7 +66,7 @@ func gockSendMessage() {
 
 func TestSendMessage(t *testing.T) {
 	ctx := context.Background()
-	obj, err := NewNotification(slackToken)
+	obj, err := NewNotification(slackToken, Options{})
 	if err != nil {
 		t.Errorf(messageFailedObj, err)
 	}
@@ -113,7 +113,7 @@ func gockUpdateMessage() {
 
 func TestUpdateMessage(t *testing.T) {
 	ctx := context.Background()
-	obj, err := NewNotification(slackToken)
+	obj, err := NewNotification(slackToken, Options{})
 	if err != nil {
 		t.Errorf(messageFailedObj, err)
 	}
@@ -147,3 +147,49 @@ func TestUpdateMessage(t *testing.T) {
 		t.Errorf(messageNotExpect, flowUpdateMessage, "channel_3", errorNotAuth, err)
 	}
 }
+
+var flowActions = "incident actions flow"
+
+func TestSendMessageActions(t *testing.T) {
+	ctx := context.Background()
+	obj, err := NewNotification(slackToken, Options{Actions: true})
+	if err != nil {
+		t.Errorf(messageFailedObj, err)
+	}
+	defer gock.Off()
+
+	attachments := []string{}
+	for i := 0; i < 4; i++ {
+		gock.New(slackURL).
+			Post(sendMessageEndpoint).
+			AddMatcher(func(req *http.Request, ereq *gock.Request) (bool, error) {
+				attachments = append(attachments, req.PostFormValue("attachments"))
+				return true, nil
+			}).
+			Reply(200).
+			BodyString(responseSuccess)
+	}
+
+	params := []entity.Notification{
+		{Key: "incident_1", Channel: "channel_1", Message: "parent"},
+		{Key: "incident_1", Channel: "channel_1", Message: "reply", Metadata: map[string]string{"timestamp": expectedThreadID}},
+		{Channel: "channel_1", Message: "parent without key"},
+		{Key: "incident_1", Channel: "channel_1", Message: "resolved parent", Resolved: true},
+	}
+	expected := []bool{true, false, false, false}
+
+	for k, param := range params {
+		_, err = obj.SendMessage(ctx, param)
+		if err != nil {
+			t.Errorf(messageNotError, flowActions, param.Message, err)
+		}
+		if k >= len(attachments) {
+			t.Fatalf(messageNotExpect, flowActions, param.Message, "request", "none")
+		}
+
+		hasActions := strings.Contains(attachments[k], `"action_id":"acknowledge"`) && strings.Contains(attachments[k], `"value":"incident_1"`)
+		if hasActions != expected[k] {
+			t.Errorf(messageNotExpect, flowActions, param.Message, expected[k], hasActions)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package slack
+
+import (
+	"context"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	sl "github.com/slack-go/slack"
+)
+
+// GetPermalink will return the permanent link of the thread main message
+func (s *Slack) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	if thread.ThreadID == "" {
+		return "", errorEmptyThreadID
+	}
+
+	return s.client.GetPermalinkContext(ctx, &sl.PermalinkParameters{
+		Channel: thread.Channel,
+		Ts:      thread.ThreadID,
+	})
+}
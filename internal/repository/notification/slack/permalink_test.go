@@ -0,0 +1,42 @@
+package slack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var getPermalinkEndpoint = "/api/chat.getPermalink"
+
+var flowGetPermalink = "get permalink flow"
+
+func TestGetPermalink(t *testing.T) {
+	ctx := context.Background()
+	obj, err := NewNotification(slackToken, Options{})
+	if err != nil {
+		t.Errorf(messageFailedObj, err)
+	}
+
+	permalink := "https://workspace.slack.com/archives/C1H9RESGL/p1503435956000247"
+	gock.New(slackURL).
+		Get(getPermalinkEndpoint).
+		MatchParam("channel", "C1H9RESGL").
+		MatchParam("message_ts", expectedThreadID).
+		Reply(200).
+		BodyString(`{"ok": true, "channel": "C1H9RESGL", "permalink": "` + permalink + `"}`)
+	defer gock.Off()
+
+	link, err := obj.GetPermalink(ctx, entity.Thread{Channel: "C1H9RESGL", ThreadID: expectedThreadID})
+	if err != nil {
+		t.Errorf(messageNotError, flowGetPermalink, "C1H9RESGL", err)
+	} else if link != permalink {
+		t.Errorf(messageNotExpect, flowGetPermalink, "C1H9RESGL", permalink, link)
+	}
+
+	_, err = obj.GetPermalink(ctx, entity.Thread{Channel: "C1H9RESGL"})
+	if err != errorEmptyThreadID {
+		t.Errorf(messageNotExpect, flowGetPermalink, "empty thread", errorEmptyThreadID, err)
+	}
+}
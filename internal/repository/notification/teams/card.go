@@ -0,0 +1,74 @@
+package teams
+
+import (
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/format"
+)
+
+const adaptiveCardType = "application/vnd.microsoft.card.adaptive"
+
+// card is Adaptive Card rendered by Microsoft Teams
+type card struct {
+	Schema  string            `json:"$schema"`
+	Type    string            `json:"type"`
+	Version string            `json:"version"`
+	Body    []interface{}     `json:"body"`
+	MSTeams map[string]string `json:"msteams"`
+}
+
+type container struct {
+	Type  string        `json:"type"`
+	Style string        `json:"style,omitempty"`
+	Bleed bool          `json:"bleed,omitempty"`
+	Items []interface{} `json:"items"`
+}
+
+type textBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+type image struct {
+	Type    string `json:"type"`
+	URL     string `json:"url"`
+	AltText string `json:"altText"`
+}
+
+// styles maps color of incident status to the closest Adaptive Card container style as card does not support custom color
+var styles = map[string]string{
+	entity.StatusWarning.Color:      "warning",
+	entity.StatusTriggered.Color:    "attention",
+	entity.StatusRecovered.Color:    "good",
+	entity.StatusAcknowledged.Color: "accent",
+}
+
+// newCard will return Adaptive Card containing the title and message inside container styled from the notification color, followed by the image
+func newCard(param entity.Notification) card {
+	items := []interface{}{}
+	if param.Title != "" {
+		items = append(items, textBlock{Type: "TextBlock", Text: format.Markdown(param.Title), Size: "Medium", Weight: "Bolder", Wrap: true})
+	}
+	if param.Message != "" {
+		items = append(items, textBlock{Type: "TextBlock", Text: format.Markdown(param.Message), Wrap: true})
+	}
+
+	body := []interface{}{
+		container{Type: "Container", Style: styles[strings.ToUpper(param.GetColor())], Bleed: true, Items: items},
+	}
+	if param.Image != "" {
+		body = append(body, image{Type: "Image", URL: param.Image, AltText: "snapshot"})
+	}
+
+	return card{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		MSTeams: map[string]string{"width": "Full"},
+	}
+}
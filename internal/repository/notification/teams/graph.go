@@ -0,0 +1,161 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+)
+
+// graphMessage is chat message of Microsoft Graph API containing the Adaptive Card as attachment
+type graphMessage struct {
+	ID          string            `json:"id,omitempty"`
+	Subject     string            `json:"subject,omitempty"`
+	WebURL      string            `json:"webUrl,omitempty"`
+	Body        graphBody         `json:"body"`
+	Attachments []graphAttachment `json:"attachments"`
+}
+
+type graphBody struct {
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+// graphAttachment contains the card as JSON string as required by Graph API
+type graphAttachment struct {
+	ID          string `json:"id"`
+	ContentType string `json:"contentType"`
+	Content     string `json:"content"`
+}
+
+// tokenExpiryMargin is how long before the expiry the Graph API token is renewed
+const tokenExpiryMargin = time.Minute
+
+// tokenScope is the delegated permissions consented to the application, offline_access keeps returning new refresh token
+const tokenScope = "https://graph.microsoft.com/.default offline_access"
+
+// newGraphMessage will return chat message referencing the card attachment from its body
+func newGraphMessage(subject string, content card) (graphMessage, error) {
+	value, err := json.Marshal(content)
+	if err != nil {
+		return graphMessage{}, err
+	}
+
+	return graphMessage{
+		Subject: subject,
+		Body: graphBody{
+			ContentType: "html",
+			Content:     `<attachment id="card"></attachment>`,
+		},
+		Attachments: []graphAttachment{{ID: "card", ContentType: adaptiveCardType, Content: string(value)}},
+	}, nil
+}
+
+// messagesURL will return Graph API URL of messages inside the channel written as <team-id>/<channel-id>
+func (t *Teams) messagesURL(channel string) (string, error) {
+	parts := strings.SplitN(channel, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("%w, got %s", errorChannelFormat, channel)
+	}
+
+	return fmt.Sprintf("%s/teams/%s/channels/%s/messages", t.options.GraphURL, url.PathEscape(parts[0]), url.PathEscape(parts[1])), nil
+}
+
+// graphRequest will call Graph API with the delegated token and decode the response into result when it is given
+func (t *Teams) graphRequest(ctx context.Context, method, endpoint string, payload, result interface{}) error {
+	token, err := t.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if payload != nil {
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	return t.do(req, result)
+}
+
+// accessToken will return cached Graph API token or redeem the refresh token for a new one when it is about to expire.
+// Refresh token rotated by Microsoft is kept in memory so the configured one is only used after restart
+func (t *Teams) accessToken(ctx context.Context) (string, error) {
+	t.tokenMutex.Lock()
+	defer t.tokenMutex.Unlock()
+
+	if t.token != "" && time.Now().Before(t.tokenExpiry) {
+		return t.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {t.options.ClientID},
+		"refresh_token": {t.refreshToken},
+		"scope":         {tokenScope},
+	}
+	if t.options.ClientSecret != "" {
+		form.Set("client_secret", t.options.ClientSecret)
+	}
+	endpoint := fmt.Sprintf("%s/%s/oauth2/v2.0/token", t.options.LoginURL, url.PathEscape(t.options.TenantID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	result := struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}{}
+	err = t.do(req, &result)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get graph token because %s", err)
+	}
+
+	t.token = result.AccessToken
+	if result.RefreshToken != "" {
+		t.refreshToken = result.RefreshToken
+	}
+	t.tokenExpiry = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - tokenExpiryMargin)
+
+	return t.token, nil
+}
+
+// do will send the request and decode the successful response into result when it is given
+func (t *Teams) do(req *http.Request, result interface{}) error {
+	response, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return httperr.New("Teams", response, body)
+	}
+	if result == nil || len(body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,94 @@
+package teams
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ModeWebhook posts cards through incoming webhooks. Incoming webhook can not reply or edit, so replies are posted as new cards
+	ModeWebhook = "webhook"
+
+	// ModeGraph posts cards through Microsoft Graph API with replies in the conversation and edited parent card. Graph only accepts channel
+	// message sent with delegated permission, so the cards are posted on behalf of the user who granted the refresh token
+	ModeGraph = "graph"
+
+	defaultGraphURL = "https://graph.microsoft.com/v1.0"
+	defaultLoginURL = "https://login.microsoftonline.com"
+	defaultTimeout  = 10 * time.Second
+)
+
+// Options defines how Microsoft Teams notification is delivered
+type Options struct {
+	// Mode is either webhook or graph
+	Mode string
+
+	// Webhooks maps notification channel to its incoming webhook URL. Unmapped channel is rejected so the payload can not choose where the card is posted
+	Webhooks map[string]string
+
+	// TenantID, ClientID and ClientSecret are credential of Azure AD application used to get Graph API token. ClientSecret is only required
+	// by confidential application. Channel is written as <team-id>/<channel-id> in graph mode
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+
+	// RefreshToken is delegated token of the posting user, e.g. a service account, with ChannelMessage.Send and ChannelMessage.ReadWrite
+	// consented. Application token of client credentials is rejected by Graph for channel messages outside of migration
+	RefreshToken string
+
+	// GraphURL and LoginURL replace the Microsoft endpoints, e.g. for national cloud
+	GraphURL string
+	LoginURL string
+
+	// Timeout is the maximum duration of each request to Microsoft Teams
+	Timeout time.Duration
+}
+
+// Teams contains dependencies needed by Microsoft Teams integration to send notification
+type Teams struct {
+	client  *http.Client
+	options Options
+
+	tokenMutex   sync.Mutex
+	token        string
+	tokenExpiry  time.Time
+	refreshToken string
+}
+
+// NewNotification will return teams object used to do Microsoft Teams integration
+func NewNotification(options Options) (*Teams, error) {
+	switch options.Mode {
+	case "", ModeWebhook:
+		options.Mode = ModeWebhook
+		if len(options.Webhooks) == 0 {
+			return nil, fmt.Errorf("Incoming webhooks are required in webhook mode")
+		}
+	case ModeGraph:
+		if options.TenantID == "" || options.ClientID == "" || options.RefreshToken == "" {
+			return nil, fmt.Errorf("Tenant ID, client ID and refresh token are required in graph mode")
+		}
+	default:
+		return nil, fmt.Errorf("Unknown teams mode %s", options.Mode)
+	}
+
+	if options.GraphURL == "" {
+		options.GraphURL = defaultGraphURL
+	}
+	if options.LoginURL == "" {
+		options.LoginURL = defaultLoginURL
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	options.GraphURL = strings.TrimSuffix(options.GraphURL, "/")
+	options.LoginURL = strings.TrimSuffix(options.LoginURL, "/")
+
+	return &Teams{
+		client:       &http.Client{Timeout: options.Timeout},
+		options:      options,
+		refreshToken: options.RefreshToken,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/threadid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var errorEmptyThreadID = fmt.Errorf("ThreadID is required")
+var errorNoWebhook = fmt.Errorf("No incoming webhook is configured for channel")
+var errorPermalinkWebhook = fmt.Errorf("Permalink is not supported by incoming webhook")
+var errorChannelFormat = fmt.Errorf("Channel must be written as <team-id>/<channel-id>")
+
+// Retryable will check whether the request may succeed when it is sent to Teams again. Missing configuration is returned again on retry
+func Retryable(err error) bool {
+	return err != errorEmptyThreadID && err != errorPermalinkWebhook && !errors.Is(err, errorNoWebhook) && !errors.Is(err, errorChannelFormat) && httperr.Retryable(err)
+}
+
+// SendMessage will send new card to Teams. If thread_id is provided, the card is replied to the conversation. Otherwise it will create a new conversation
+func (t *Teams) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	threadID := param.Metadata["timestamp"]
+	if t.options.Mode == ModeWebhook {
+		return t.sendWebhook(ctx, param, threadID)
+	}
+
+	endpoint, err := t.messagesURL(param.Channel)
+	if err != nil {
+		return "", err
+	}
+
+	subject := param.Title
+	if threadID != "" {
+		endpoint = fmt.Sprintf("%s/%s/replies", endpoint, threadID)
+		subject = ""
+	}
+
+	message, err := newGraphMessage(subject, newCard(param))
+	if err != nil {
+		return "", err
+	}
+
+	result := graphMessage{}
+	err = t.graphRequest(ctx, http.MethodPost, endpoint, message, &result)
+	if err != nil {
+		return threadID, err
+	}
+	if threadID != "" {
+		return threadID, nil
+	}
+
+	return result.ID, nil
+}
+
+// UpdateMessage will update the card of conversation provided. Incoming webhook can not edit its card so the update is skipped in webhook mode
+func (t *Teams) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	threadID, ok := param.Metadata["timestamp"]
+	if !ok || threadID == "" {
+		return errorEmptyThreadID
+	}
+	if t.options.Mode == ModeWebhook {
+		log.Debugf("Skipped updating teams card %s as incoming webhook can not edit card", threadID)
+		return nil
+	}
+
+	endpoint, err := t.messagesURL(param.Channel)
+	if err != nil {
+		return err
+	}
+
+	message, err := newGraphMessage(param.Title, newCard(param))
+	if err != nil {
+		return err
+	}
+
+	return t.graphRequest(ctx, http.MethodPatch, fmt.Sprintf("%s/%s", endpoint, threadID), message, nil)
+}
+
+// GetPermalink will return link of the conversation parent card. It is only supported in graph mode
+func (t *Teams) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	if thread.ThreadID == "" {
+		return "", errorEmptyThreadID
+	}
+	if t.options.Mode == ModeWebhook {
+		return "", errorPermalinkWebhook
+	}
+
+	endpoint, err := t.messagesURL(thread.Channel)
+	if err != nil {
+		return "", err
+	}
+
+	result := graphMessage{}
+	err = t.graphRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%s", endpoint, thread.ThreadID), nil, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.WebURL, nil
+}
+
+// sendWebhook will post the card to incoming webhook of the channel. Reply is posted as new card quoting the title since incoming webhook has no conversation,
+// and generated id is returned as the thread id of new conversation
+func (t *Teams) sendWebhook(ctx context.Context, param entity.Notification, threadID string) (string, error) {
+	endpoint, ok := t.options.Webhooks[param.Channel]
+	if !ok {
+		return "", fmt.Errorf("%w %s", errorNoWebhook, param.Channel)
+	}
+
+	if threadID != "" {
+		param.Title = fmt.Sprintf("RE: %s", param.Title)
+	} else {
+		threadID = threadid.New()
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{"contentType": adaptiveCardType, "content": newCard(param)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = t.do(req, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return threadID, nil
+}
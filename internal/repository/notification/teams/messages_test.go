@@ -0,0 +1,259 @@
+package teams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+)
+
+var messageFailedObj = "Failed to create notification object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+var flowGetPermalink = "get permalink flow"
+
+// teamsServer is local stand-in of Microsoft login and Graph API recording every request it receives
+type teamsServer struct {
+	mutex    sync.Mutex
+	requests []string
+	bodies   []string
+	tokens   int
+}
+
+func (s *teamsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.URL.Path == "/login/tenant_1/oauth2/v2.0/token" {
+		// Only delegated token is accepted for channel messages like Microsoft Graph does
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("grant_type") != "refresh_token" || form.Get("refresh_token") != fmt.Sprintf("refresh_%d", s.tokens+1) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s.tokens++
+		fmt.Fprintf(w, `{"access_token": "token_%d", "refresh_token": "refresh_%d", "expires_in": 3600}`, s.tokens, s.tokens+1)
+		return
+	}
+
+	s.requests = append(s.requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.Header.Get("Authorization")))
+	s.bodies = append(s.bodies, string(body))
+	switch {
+	case strings.Contains(r.URL.Path, "channel_2"):
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	case r.Method == http.MethodGet:
+		fmt.Fprint(w, `{"id": "1600000000000", "webUrl": "https://teams.microsoft.com/l/message/1600000000000"}`)
+	case r.Method == http.MethodPatch:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fmt.Fprint(w, `{"id": "1600000000000"}`)
+	}
+}
+
+func newGraphNotification(t *testing.T, server *httptest.Server) *Teams {
+	obj, err := NewNotification(Options{
+		Mode:         ModeGraph,
+		TenantID:     "tenant_1",
+		ClientID:     "client_1",
+		RefreshToken: "refresh_1",
+		GraphURL:     server.URL + "/graph",
+		LoginURL:     server.URL + "/login",
+	})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	return obj
+}
+
+func TestNewNotification(t *testing.T) {
+	options := []Options{
+		{},
+		{Webhooks: map[string]string{"oncall": "https://example.webhook.office.com/oncall"}},
+		{Mode: ModeGraph, TenantID: "tenant_1", ClientID: "client_1", RefreshToken: "refresh_1"},
+		{Mode: ModeGraph, TenantID: "tenant_1", ClientID: "client_1", ClientSecret: "secret_1"},
+		{Mode: "bot"},
+	}
+	expected := []bool{false, true, true, false, false}
+
+	for k, option := range options {
+		_, err := NewNotification(option)
+		if (err == nil) != expected[k] {
+			t.Errorf(messageNotExpect, "new notification flow", option.Mode, expected[k], err)
+		}
+	}
+}
+
+func TestSendMessageGraph(t *testing.T) {
+	stub := &teamsServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newGraphNotification(t, server)
+
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Channel: "team_1/channel_1", Title: "*CPU usage*", Message: "From: *Datadog*", Color: entity.StatusTriggered.Color})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "parent", err)
+	} else if threadID != "1600000000000" {
+		t.Errorf(messageNotExpect, flowSendMessage, "parent", "1600000000000", threadID)
+	}
+
+	threadID, err = obj.SendMessage(ctx, entity.Notification{Channel: "team_1/channel_1", Message: "detail", Image: "http://image.png", Metadata: map[string]string{"timestamp": "1600000000000"}})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "reply", err)
+	} else if threadID != "1600000000000" {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply", "1600000000000", threadID)
+	}
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "team_1/channel_2"})
+	if delay, ok := httperr.RetryAfter(err); !ok || delay != 7*time.Second || !Retryable(err) {
+		t.Errorf(messageNotExpect, flowSendMessage, "rate limited", 7*time.Second, err)
+	}
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "channel_1"})
+	if err == nil || Retryable(err) {
+		t.Errorf(messageNotExpect, flowSendMessage, "channel without team", "error", err)
+	}
+
+	expected := []string{
+		"POST /graph/teams/team_1/channels/channel_1/messages Bearer token_1",
+		"POST /graph/teams/team_1/channels/channel_1/messages/1600000000000/replies Bearer token_1",
+		"POST /graph/teams/team_1/channels/channel_2/messages Bearer token_1",
+	}
+	if strings.Join(stub.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(messageNotExpect, flowSendMessage, "requests", expected, stub.requests)
+	}
+	if stub.tokens != 1 {
+		t.Errorf(messageNotExpect, flowSendMessage, "token requests", 1, stub.tokens)
+	}
+
+	// Expired token is renewed with the rotated refresh token
+	obj.tokenExpiry = time.Now()
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "team_1/channel_1", Title: "title"})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "renewed token", err)
+	} else if last := stub.requests[len(stub.requests)-1]; !strings.HasSuffix(last, "Bearer token_2") {
+		t.Errorf(messageNotExpect, flowSendMessage, "renewed token", "token_2", last)
+	}
+
+	message := graphMessage{}
+	json.Unmarshal([]byte(stub.bodies[0]), &message)
+	content := card{}
+	json.Unmarshal([]byte(message.Attachments[0].Content), &content)
+	if message.Subject != "*CPU usage*" || message.Attachments[0].ContentType != adaptiveCardType || !strings.Contains(message.Body.Content, `<attachment id="card">`) {
+		t.Errorf(messageNotExpect, flowSendMessage, "parent body", "card attachment", stub.bodies[0])
+	}
+	if !strings.Contains(message.Attachments[0].Content, `"style":"attention"`) || !strings.Contains(message.Attachments[0].Content, "From: **Datadog**") {
+		t.Errorf(messageNotExpect, flowSendMessage, "parent card", "attention style", message.Attachments[0].Content)
+	}
+	if !strings.Contains(stub.bodies[1], "http://image.png") {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply card", "image", stub.bodies[1])
+	}
+}
+
+func TestUpdateMessageGraph(t *testing.T) {
+	stub := &teamsServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newGraphNotification(t, server)
+
+	err := obj.UpdateMessage(ctx, entity.Notification{Channel: "team_1/channel_1", Title: "title", Color: entity.StatusRecovered.Color, Metadata: map[string]string{"timestamp": "1600000000000"}})
+	if err != nil {
+		t.Errorf(messageNotError, flowUpdateMessage, "parent", err)
+	} else if len(stub.requests) != 1 || stub.requests[0] != "PATCH /graph/teams/team_1/channels/channel_1/messages/1600000000000 Bearer token_1" {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "parent", "PATCH", stub.requests)
+	} else if !strings.Contains(stub.bodies[0], `\"style\":\"good\"`) {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "parent", "good style", stub.bodies[0])
+	}
+
+	err = obj.UpdateMessage(ctx, entity.Notification{Channel: "team_1/channel_1"})
+	if err != errorEmptyThreadID {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "without thread", errorEmptyThreadID, err)
+	}
+}
+
+func TestGetPermalink(t *testing.T) {
+	stub := &teamsServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj := newGraphNotification(t, server)
+
+	link, err := obj.GetPermalink(ctx, entity.Thread{Channel: "team_1/channel_1", ThreadID: "1600000000000"})
+	if err != nil {
+		t.Errorf(messageNotError, flowGetPermalink, "graph", err)
+	} else if link != "https://teams.microsoft.com/l/message/1600000000000" {
+		t.Errorf(messageNotExpect, flowGetPermalink, "graph", "web url", link)
+	}
+
+	webhook, _ := NewNotification(Options{Webhooks: map[string]string{"channel_1": server.URL + "/webhook/channel_1"}})
+	_, err = webhook.GetPermalink(ctx, entity.Thread{Channel: "channel_1", ThreadID: "1600000000000"})
+	if err == nil || Retryable(err) {
+		t.Errorf(messageNotExpect, flowGetPermalink, "webhook", "error", err)
+	}
+}
+
+func TestSendMessageWebhook(t *testing.T) {
+	stub := &teamsServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj, err := NewNotification(Options{Webhooks: map[string]string{"oncall": server.URL + "/webhook/oncall"}})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Channel: "oncall", Title: "title"})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "webhook parent", err)
+	} else if len(threadID) != 16 {
+		t.Errorf(messageNotExpect, flowSendMessage, "webhook parent", "generated thread id", threadID)
+	}
+
+	replyID, err := obj.SendMessage(ctx, entity.Notification{Channel: "oncall", Title: "title", Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "webhook reply", err)
+	} else if replyID != threadID {
+		t.Errorf(messageNotExpect, flowSendMessage, "webhook reply", threadID, replyID)
+	}
+
+	// Channel sent in the payload must not be used as the webhook URL
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: server.URL + "/webhook/unmapped", Title: "title"})
+	if err == nil || Retryable(err) {
+		t.Errorf(messageNotExpect, flowSendMessage, "unmapped webhook", "error", err)
+	}
+
+	err = obj.UpdateMessage(ctx, entity.Notification{Channel: "oncall", Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Errorf(messageNotError, flowUpdateMessage, "webhook", err)
+	}
+
+	expected := []string{"POST /webhook/oncall ", "POST /webhook/oncall "}
+	if strings.Join(stub.requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(messageNotExpect, flowSendMessage, "webhook requests", expected, stub.requests)
+	}
+	if !strings.Contains(stub.bodies[0], `"contentType":"application/vnd.microsoft.card.adaptive"`) || !strings.Contains(stub.bodies[1], `"text":"RE: title"`) {
+		t.Errorf(messageNotExpect, flowSendMessage, "webhook body", "card", stub.bodies)
+	}
+}
@@ -0,0 +1,13 @@
+package threadid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// New will return random thread id for notification channel which does not return identifier of the posted message
+func New() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
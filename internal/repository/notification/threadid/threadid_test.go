@@ -0,0 +1,12 @@
+package threadid
+
+import (
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	first, second := New(), New()
+	if len(first) != 16 || first == second {
+		t.Errorf("Thread id expecting random 16 hex characters, got %s and %s instead", first, second)
+	}
+}
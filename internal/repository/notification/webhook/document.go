@@ -0,0 +1,32 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// DocumentVersion is version of the document schema. It is increased when a field is changed or removed, not when a field is added
+const DocumentVersion = "1"
+
+// Event of the document telling the receiver what to do with the notification
+const (
+	EventParent = "parent"
+	EventReply  = "reply"
+	EventUpdate = "update"
+)
+
+// Document is the JSON body posted to the receiver. It contains every field of the notification including the incident key
+type Document struct {
+	Version  string    `json:"version"`
+	Event    string    `json:"event"`
+	ThreadID string    `json:"thread_id"`
+	SentAt   time.Time `json:"sent_at"`
+
+	entity.Notification
+}
+
+// response is the optional JSON body responded by the receiver of parent event
+type response struct {
+	ThreadID string `json:"thread_id"`
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultSignatureHeader = "X-Alert-Thread-Signature"
+	defaultTimeout         = 10 * time.Second
+)
+
+// Options defines where and how the notification document is posted
+type Options struct {
+	// URL receives every document. ParentURL, ReplyURL and UpdateURL replace it for their event when they are set
+	URL       string
+	ParentURL string
+	ReplyURL  string
+	UpdateURL string
+
+	// Headers is sent with every request, e.g. static authorization header of the receiver
+	Headers map[string]string
+
+	// Secret signs the request body with hex encoded HMAC-SHA256 sent as sha256=<signature> in SignatureHeader. Request is not signed when it is empty
+	Secret          string
+	SignatureHeader string
+
+	// Timeout is the maximum duration of each request to the receiver
+	Timeout time.Duration
+}
+
+// Webhook contains dependencies needed to post notification document to the receiver
+type Webhook struct {
+	client  *http.Client
+	options Options
+}
+
+// NewNotification will return webhook object used to post notification document to the receiver
+func NewNotification(options Options) (*Webhook, error) {
+	for _, url := range []*string{&options.ParentURL, &options.ReplyURL, &options.UpdateURL} {
+		if *url == "" {
+			*url = options.URL
+		}
+		if *url == "" {
+			return nil, fmt.Errorf("Webhook URL is required for parent, reply and update event")
+		}
+	}
+	if options.SignatureHeader == "" {
+		options.SignatureHeader = defaultSignatureHeader
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	return &Webhook{
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
+	}, nil
+}
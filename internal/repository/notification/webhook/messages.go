@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/threadid"
+)
+
+var errorEmptyThreadID = fmt.Errorf("ThreadID is required")
+
+// SendMessage will post parent document when thread_id is not provided and return the thread id responded by the receiver or a generated one.
+// Otherwise it will post reply document of the thread
+func (w *Webhook) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	threadID := param.Metadata["timestamp"]
+	if threadID != "" {
+		_, err := w.post(ctx, w.options.ReplyURL, EventReply, threadID, param)
+		return threadID, err
+	}
+
+	body, err := w.post(ctx, w.options.ParentURL, EventParent, "", param)
+	if err != nil {
+		return "", err
+	}
+
+	result := response{}
+	if json.Unmarshal(body, &result) == nil && result.ThreadID != "" {
+		return result.ThreadID, nil
+	}
+
+	return threadid.New(), nil
+}
+
+// UpdateMessage will post update document containing the latest parent of the thread
+func (w *Webhook) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	threadID, ok := param.Metadata["timestamp"]
+	if !ok || threadID == "" {
+		return errorEmptyThreadID
+	}
+
+	_, err := w.post(ctx, w.options.UpdateURL, EventUpdate, threadID, param)
+	return err
+}
+
+// post will send the signed document of the event and return the response body
+func (w *Webhook) post(ctx context.Context, url, event, threadID string, param entity.Notification) ([]byte, error) {
+	body, err := json.Marshal(Document{
+		Version:      DocumentVersion,
+		Event:        event,
+		ThreadID:     threadID,
+		SentAt:       time.Now().UTC(),
+		Notification: param,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range w.options.Headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.options.Secret != "" {
+		req.Header.Set(w.options.SignatureHeader, "sha256="+Sign(w.options.Secret, body))
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, httperr.New("Webhook", res, body)
+	}
+
+	return body, nil
+}
+
+// Sign will return hex encoded HMAC-SHA256 signature of the body so the receiver can verify the document
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
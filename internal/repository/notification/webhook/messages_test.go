@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+	"github.com/alvintzz/alert-thread/internal/repository/notification/httperr"
+)
+
+var messageFailedObj = "Failed to create notification object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected %+v, got %+v"
+
+var flowSendMessage = "send message flow"
+var flowUpdateMessage = "update message flow"
+
+// receiver is local stand-in of webhook receiver recording every document it receives
+type receiver struct {
+	mutex      sync.Mutex
+	paths      []string
+	documents  []Document
+	signatures []string
+	headers    []string
+}
+
+func (s *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	document := Document{}
+	json.Unmarshal(body, &document)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.paths = append(s.paths, r.URL.Path)
+	s.documents = append(s.documents, document)
+	s.signatures = append(s.signatures, fmt.Sprintf("%t", r.Header.Get("X-Signature") == "sha256="+Sign("secret_1", body)))
+	s.headers = append(s.headers, r.Header.Get("Authorization"))
+
+	switch {
+	case document.Channel == "unavailable":
+		w.Header().Set("Retry-After", "5")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case document.Channel == "tracked":
+		fmt.Fprint(w, `{"thread_id": "remediation-42"}`)
+	default:
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func TestNewNotification(t *testing.T) {
+	options := []Options{
+		{URL: "http://localhost/events"},
+		{ParentURL: "http://localhost/parent", ReplyURL: "http://localhost/reply", UpdateURL: "http://localhost/update"},
+		{ParentURL: "http://localhost/parent"},
+		{},
+	}
+	expected := []bool{true, true, false, false}
+
+	for k, option := range options {
+		_, err := NewNotification(option)
+		if (err == nil) != expected[k] {
+			t.Errorf(messageNotExpect, "new notification flow", fmt.Sprintf("options %d", k), expected[k], err)
+		}
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	stub := &receiver{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	ctx := context.Background()
+	obj, err := NewNotification(Options{
+		URL:             server.URL + "/events",
+		UpdateURL:       server.URL + "/updates",
+		Headers:         map[string]string{"Authorization": "Bearer token_1"},
+		Secret:          "secret_1",
+		SignatureHeader: "X-Signature",
+	})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	threadID, err := obj.SendMessage(ctx, entity.Notification{Key: "incident_1", Channel: "tracked", Title: "title", Message: "summary", Color: entity.StatusTriggered.Color})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "tracked", err)
+	} else if threadID != "remediation-42" {
+		t.Errorf(messageNotExpect, flowSendMessage, "tracked", "remediation-42", threadID)
+	}
+
+	generatedID, err := obj.SendMessage(ctx, entity.Notification{Key: "incident_2", Channel: "untracked"})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "untracked", err)
+	} else if len(generatedID) != 16 {
+		t.Errorf(messageNotExpect, flowSendMessage, "untracked", "generated thread id", generatedID)
+	}
+
+	replyID, err := obj.SendMessage(ctx, entity.Notification{Key: "incident_1", Channel: "tracked", Message: "detail", Image: "http://image.png", Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Errorf(messageNotError, flowSendMessage, "reply", err)
+	} else if replyID != threadID {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply", threadID, replyID)
+	}
+
+	err = obj.UpdateMessage(ctx, entity.Notification{Key: "incident_1", Channel: "tracked", Color: entity.StatusRecovered.Color, Metadata: map[string]string{"timestamp": threadID}})
+	if err != nil {
+		t.Errorf(messageNotError, flowUpdateMessage, "tracked", err)
+	}
+
+	_, err = obj.SendMessage(ctx, entity.Notification{Channel: "unavailable"})
+	if delay, ok := httperr.RetryAfter(err); !ok || delay != 5*time.Second {
+		t.Errorf(messageNotExpect, flowSendMessage, "unavailable", 5*time.Second, err)
+	}
+
+	expected := []string{
+		"/events parent incident_1 tracked  FF0000",
+		"/events parent incident_2 untracked  ",
+		"/events reply incident_1 tracked remediation-42 ",
+		"/updates update incident_1 tracked remediation-42 00BF85",
+	}
+	for k, line := range expected {
+		document := stub.documents[k]
+		result := fmt.Sprintf("%s %s %s %s %s %s", stub.paths[k], document.Event, document.Key, document.Channel, document.ThreadID, document.Color)
+		if result != line {
+			t.Errorf(messageNotExpect, flowSendMessage, fmt.Sprintf("document %d", k), line, result)
+		}
+		if document.Version != DocumentVersion || document.SentAt.IsZero() || stub.signatures[k] != "true" || stub.headers[k] != "Bearer token_1" {
+			t.Errorf(messageNotExpect, flowSendMessage, fmt.Sprintf("document %d", k), "signed versioned document", document)
+		}
+	}
+	if stub.documents[2].Image != "http://image.png" || stub.documents[2].Metadata["timestamp"] != "remediation-42" {
+		t.Errorf(messageNotExpect, flowSendMessage, "reply document", "image and metadata", stub.documents[2])
+	}
+
+	err = obj.UpdateMessage(ctx, entity.Notification{Channel: "tracked"})
+	if err != errorEmptyThreadID {
+		t.Errorf(messageNotExpect, flowUpdateMessage, "without thread", errorEmptyThreadID, err)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	body, _ := json.Marshal(Document{Version: DocumentVersion, Event: EventParent, Notification: entity.Notification{Key: "incident_1", Title: "title"}})
+	for _, field := range []string{`"version":"1"`, `"event":"parent"`, `"key":"incident_1"`, `"title":"title"`, `"thread_id":""`} {
+		if !strings.Contains(string(body), field) {
+			t.Errorf(messageNotExpect, "document flow", field, field, string(body))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreFailedEvent will save or update the failed event inside the chosen storage so it is not lost
+func (b *Storage) StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(failedEventBucket).Put([]byte(event.ID), value)
+	})
+}
+
+// GetFailedEvent will return the failed event saved inside the chosen storage. Empty object is returned when it is not found
+func (b *Storage) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	event := entity.FailedEvent{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(failedEventBucket).Get([]byte(id))
+		if value == nil {
+			return nil
+		}
+
+		return json.Unmarshal(value, &event)
+	})
+	if err != nil {
+		return entity.FailedEvent{}, err
+	}
+
+	return event, nil
+}
+
+// ListFailedEvents will return every failed event saved inside the chosen storage ordered by the failure time
+func (b *Storage) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	events := []entity.FailedEvent{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(failedEventBucket).ForEach(func(key, value []byte) error {
+			event := entity.FailedEvent{}
+			err := json.Unmarshal(value, &event)
+			if err != nil {
+				return err
+			}
+
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].FailedAt.Before(events[j].FailedAt)
+	})
+
+	return events, nil
+}
+
+// RemoveFailedEvent will remove the failed event saved inside the chosen storage
+func (b *Storage) RemoveFailedEvent(ctx context.Context, id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(failedEventBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,82 @@
+package boltdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var flowStoreFailedEvent = "store failed event flow"
+var flowListFailedEvents = "list failed events flow"
+var flowRemoveFailedEvent = "remove failed event flow"
+
+func TestStoreFailedEvent(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	err := storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", Key: "incident_1", Payload: []byte(`{"cycle_key":"incident_1"}`), Error: "timeout"})
+	if err != nil {
+		t.Errorf(messageNotError, flowStoreFailedEvent, "event_1", err)
+	}
+
+	// Failed event must survive the storage being closed and reopened like a service restart
+	storage.Close()
+	storage, err = NewStorage(filepath.Join(dir, "incidents.db"))
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+	defer storage.Close()
+
+	event, err := storage.GetFailedEvent(ctx, "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowStoreFailedEvent, "event_1", err)
+	} else if event.Key != "incident_1" || event.Error != "timeout" || string(event.Payload) != `{"cycle_key":"incident_1"}` {
+		t.Errorf(messageNotExpect, flowStoreFailedEvent, "event_1", "incident_1", event)
+	}
+
+	event, err = storage.GetFailedEvent(ctx, "event_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowStoreFailedEvent, "event_2", err)
+	} else if event.ID != "" {
+		t.Errorf(messageNotExpect, flowStoreFailedEvent, "event_2", "", event.ID)
+	}
+}
+
+func TestListFailedEvents(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+	defer storage.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", FailedAt: now})
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_2", FailedAt: now.Add(-time.Minute)})
+
+	events, err := storage.ListFailedEvents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListFailedEvents, "all", err)
+	} else if len(events) != 2 || events[0].ID != "event_2" || events[1].ID != "event_1" {
+		t.Errorf(messageNotExpect, flowListFailedEvents, "all", "[event_2 event_1]", events)
+	}
+}
+
+func TestRemoveFailedEvent(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1"})
+
+	err := storage.RemoveFailedEvent(ctx, "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveFailedEvent, "event_1", err)
+	} else if event, _ := storage.GetFailedEvent(ctx, "event_1"); event.ID != "" {
+		t.Errorf(messageNotExpect, flowRemoveFailedEvent, "event_1", "", event.ID)
+	}
+}
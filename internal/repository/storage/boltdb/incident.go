@@ -0,0 +1,69 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// GetIncident will return object of Incident saved inside the chosen storage
+func (b *Storage) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
+	incident := entity.Incident{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(incidentBucket).Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+
+		return json.Unmarshal(value, &incident)
+	})
+	if err != nil {
+		return entity.Incident{}, err
+	}
+
+	return incident, nil
+}
+
+// RegisterIncident will register or update object of Incident saved inside the chosen storage
+func (b *Storage) RegisterIncident(ctx context.Context, key string, incident entity.Incident) error {
+	value, err := json.Marshal(incident)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentBucket).Put([]byte(key), value)
+	})
+}
+
+// RemoveIncident will remove object of Incident saved inside the chosen storage
+func (b *Storage) RemoveIncident(ctx context.Context, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentBucket).Delete([]byte(key))
+	})
+}
+
+// ListIncidents will return every Incident saved inside the chosen storage mapped by its key
+func (b *Storage) ListIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	incidents := map[string]entity.Incident{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentBucket).ForEach(func(key, value []byte) error {
+			incident := entity.Incident{}
+			err := json.Unmarshal(value, &incident)
+			if err != nil {
+				return err
+			}
+
+			incidents[string(key)] = incident
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
@@ -0,0 +1,156 @@
+package boltdb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageFailedObj = "Failed to create storage object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected \"%+v\", got \"%+v\""
+
+var flowGetIncident = "get incident flow"
+var flowRegisterIncident = "register incident flow"
+var flowRemoveIncident = "remove incident flow"
+var flowReopenStorage = "reopen storage flow"
+
+func newTestStorage(t *testing.T) (*Storage, string) {
+	dir, err := ioutil.TempDir("", "boltdb")
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	path := filepath.Join(dir, "incidents.db")
+	storage, err := NewStorage(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	return storage, dir
+}
+
+func TestGetIncident(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1"})
+
+	incident, err := storage.GetIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetIncident, "incident_1", err)
+	} else if incident.Title != "Incident 1" {
+		t.Errorf(messageNotExpect, flowGetIncident, "incident_1", "Incident 1", incident.Title)
+	}
+
+	incident, err = storage.GetIncident(ctx, "incident_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetIncident, "incident_2", err)
+	} else if incident.Title != "" {
+		t.Errorf(messageNotExpect, flowGetIncident, "incident_2", "", incident.Title)
+	}
+}
+
+func TestRegisterIncident(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	expected := entity.Incident{
+		Title:      "Incident 1",
+		Threads:    []entity.Thread{{Channel: "C1H9RESGL", ThreadID: "1503435956.000247"}},
+		Vendor:     "Datadog",
+		Status:     entity.StatusTriggered,
+		LastUpdate: time.Now().Round(0),
+	}
+
+	err := storage.RegisterIncident(ctx, "incident_1", expected)
+	if err != nil {
+		t.Errorf(messageNotError, flowRegisterIncident, "incident_1", err)
+	}
+
+	err = storage.RegisterIncident(ctx, "incident_2", entity.Incident{Title: "Incident 2"})
+	if err != nil {
+		t.Errorf(messageNotError, flowRegisterIncident, "incident_2", err)
+	}
+	err = storage.RegisterIncident(ctx, "incident_2", entity.Incident{Title: "Incident 2 New"})
+	if err != nil {
+		t.Errorf(messageNotError, flowRegisterIncident, "incident_2", err)
+	}
+
+	// Incident must survive the storage being closed and reopened like a service restart
+	storage.Close()
+	storage, err = NewStorage(filepath.Join(dir, "incidents.db"))
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+	defer storage.Close()
+
+	incident, err := storage.GetIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowReopenStorage, "incident_1", err)
+	} else if !incident.LastUpdate.Equal(expected.LastUpdate) || !reflect.DeepEqual(incident.Threads, expected.Threads) || incident.Status != expected.Status {
+		t.Errorf(messageNotExpect, flowReopenStorage, "incident_1", expected, incident)
+	}
+
+	incident, _ = storage.GetIncident(ctx, "incident_2")
+	if incident.Title != "Incident 2 New" {
+		t.Errorf(messageNotExpect, flowReopenStorage, "incident_2", "Incident 2 New", incident.Title)
+	}
+}
+
+func TestRemoveIncident(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1"})
+
+	err := storage.RemoveIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveIncident, "incident_1", err)
+	} else if incident, _ := storage.GetIncident(ctx, "incident_1"); incident.Title != "" {
+		t.Errorf(messageNotExpect, flowRemoveIncident, "incident_1", "", incident.Title)
+	}
+
+	err = storage.RemoveIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveIncident, "incident_1", err)
+	}
+}
+
+var flowListIncidents = "list incidents flow"
+
+func TestListIncidents(t *testing.T) {
+	storage, dir := newTestStorage(t)
+	defer os.RemoveAll(dir)
+	defer storage.Close()
+
+	ctx := context.Background()
+	incidents, err := storage.ListIncidents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListIncidents, "empty", err)
+	} else if len(incidents) != 0 {
+		t.Errorf(messageNotExpect, flowListIncidents, "empty", 0, len(incidents))
+	}
+
+	storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1"})
+	storage.RegisterIncident(ctx, "incident_2", entity.Incident{Title: "Incident 2"})
+
+	incidents, err = storage.ListIncidents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListIncidents, "all", err)
+	} else if len(incidents) != 2 || incidents["incident_2"].Title != "Incident 2" {
+		t.Errorf(messageNotExpect, flowListIncidents, "all", 2, incidents)
+	}
+}
@@ -0,0 +1,55 @@
+package boltdb
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// incidentBucket is the bucket name where every incident is saved
+	incidentBucket = []byte("incidents")
+
+	// failedEventBucket is the bucket name where every failed event is saved
+	failedEventBucket = []byte("failed_events")
+
+	// muteBucket is the bucket name where every mute is saved
+	muteBucket = []byte("mutes")
+)
+
+// Storage is object Storage using embedded BoltDB file
+type Storage struct {
+	db *bolt.DB
+}
+
+// NewStorage will return storage implementation using BoltDB file located in the path
+func NewStorage(path string) (*Storage, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open database %s because %s", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{incidentBucket, failedEventBucket, muteBucket} {
+			_, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("Failed to create bucket because %s", err)
+	}
+
+	return &Storage{
+		db: db,
+	}, nil
+}
+
+// Close will release the database file so other process can open it
+func (b *Storage) Close() error {
+	return b.db.Close()
+}
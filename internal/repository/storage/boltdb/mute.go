@@ -0,0 +1,56 @@
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// StoreMute will save or update the mute inside the chosen storage
+func (b *Storage) StoreMute(ctx context.Context, mute entity.Mute) error {
+	value, err := json.Marshal(mute)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(muteBucket).Put([]byte(mute.ID), value)
+	})
+}
+
+// ListMutes will return every mute saved inside the chosen storage ordered by the creation time
+func (b *Storage) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	mutes := []entity.Mute{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(muteBucket).ForEach(func(key, value []byte) error {
+			mute := entity.Mute{}
+			err := json.Unmarshal(value, &mute)
+			if err != nil {
+				return err
+			}
+
+			mutes = append(mutes, mute)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(mutes, func(i, j int) bool {
+		return mutes[i].CreatedAt.Before(mutes[j].CreatedAt)
+	})
+
+	return mutes, nil
+}
+
+// RemoveMute will remove the mute saved inside the chosen storage
+func (b *Storage) RemoveMute(ctx context.Context, id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(muteBucket).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,52 @@
+package gmap
+
+import (
+	"context"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// StoreFailedEvent will save or update the failed event inside the chosen storage so it is not lost
+func (m *Storage) StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	m.FailedEvents[event.ID] = event
+
+	return nil
+}
+
+// GetFailedEvent will return the failed event saved inside the chosen storage. Empty object is returned when it is not found
+func (m *Storage) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	return m.FailedEvents[id], nil
+}
+
+// ListFailedEvents will return every failed event saved inside the chosen storage ordered by the failure time
+func (m *Storage) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	events := make([]entity.FailedEvent, 0, len(m.FailedEvents))
+	for _, event := range m.FailedEvents {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].FailedAt.Before(events[j].FailedAt)
+	})
+
+	return events, nil
+}
+
+// RemoveFailedEvent will remove the failed event saved inside the chosen storage
+func (m *Storage) RemoveFailedEvent(ctx context.Context, id string) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	delete(m.FailedEvents, id)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package gmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var flowStoreFailedEvent = "store failed event flow"
+var flowGetFailedEvent = "get failed event flow"
+var flowListFailedEvents = "list failed events flow"
+var flowRemoveFailedEvent = "remove failed event flow"
+
+func TestStoreFailedEvent(t *testing.T) {
+	storage, _ := NewStorage()
+
+	ctx := context.Background()
+	err := storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", Key: "incident_1"})
+	if err != nil {
+		t.Errorf(messageNotError, flowStoreFailedEvent, "event_1", err)
+	} else if value, ok := storage.FailedEvents["event_1"]; !(ok && value.Key == "incident_1") {
+		t.Errorf(messageNotExpect, flowStoreFailedEvent, "event_1", "incident_1", value.Key)
+	}
+}
+
+func TestGetFailedEvent(t *testing.T) {
+	storage, _ := NewStorage()
+	storage.FailedEvents["event_1"] = entity.FailedEvent{ID: "event_1", Key: "incident_1"}
+
+	ctx := context.Background()
+	event, err := storage.GetFailedEvent(ctx, "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetFailedEvent, "event_1", err)
+	} else if event.Key != "incident_1" {
+		t.Errorf(messageNotExpect, flowGetFailedEvent, "event_1", "incident_1", event.Key)
+	}
+
+	event, err = storage.GetFailedEvent(ctx, "event_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetFailedEvent, "event_2", err)
+	} else if event.ID != "" {
+		t.Errorf(messageNotExpect, flowGetFailedEvent, "event_2", "", event.ID)
+	}
+}
+
+func TestListFailedEvents(t *testing.T) {
+	storage, _ := NewStorage()
+	now := time.Now()
+	storage.FailedEvents["event_1"] = entity.FailedEvent{ID: "event_1", FailedAt: now}
+	storage.FailedEvents["event_2"] = entity.FailedEvent{ID: "event_2", FailedAt: now.Add(-time.Minute)}
+
+	events, err := storage.ListFailedEvents(context.Background())
+	if err != nil {
+		t.Errorf(messageNotError, flowListFailedEvents, "all", err)
+	} else if len(events) != 2 || events[0].ID != "event_2" || events[1].ID != "event_1" {
+		t.Errorf(messageNotExpect, flowListFailedEvents, "all", "[event_2 event_1]", events)
+	}
+}
+
+func TestRemoveFailedEvent(t *testing.T) {
+	storage, _ := NewStorage()
+	storage.FailedEvents["event_1"] = entity.FailedEvent{ID: "event_1"}
+
+	err := storage.RemoveFailedEvent(context.Background(), "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveFailedEvent, "event_1", err)
+	} else if _, ok := storage.FailedEvents["event_1"]; ok {
+		t.Errorf(messageNotExpect, flowRemoveFailedEvent, "event_1", "removed", "exists")
+	}
+}
@@ -8,13 +8,17 @@ import (
 
 // Storage is object Storage using Golang's Map
 type Storage struct {
-	Mutex     sync.Mutex
-	Incidents map[string]entity.Incident
+	Mutex        sync.Mutex
+	Incidents    map[string]entity.Incident
+	FailedEvents map[string]entity.FailedEvent
+	Mutes        map[string]entity.Mute
 }
 
 // NewStorage will return storage implementation using Golang's Map
 func NewStorage() (*Storage, error) {
 	return &Storage{
-		Incidents: map[string]entity.Incident{},
+		Incidents:    map[string]entity.Incident{},
+		FailedEvents: map[string]entity.FailedEvent{},
+		Mutes:        map[string]entity.Mute{},
 	}, nil
 }
@@ -13,7 +13,7 @@ func (m *Storage) GetIncident(ctx context.Context, key string) (entity.Incident,
 
 	value, ok := m.Incidents[key]
 	if ok {
-		return value, nil
+		return copyIncident(value), nil
 	}
 
 	return entity.Incident{}, nil
@@ -24,7 +24,7 @@ func (m *Storage) RegisterIncident(ctx context.Context, key string, incident ent
 	m.Mutex.Lock()
 	defer m.Mutex.Unlock()
 
-	m.Incidents[key] = incident
+	m.Incidents[key] = copyIncident(incident)
 
 	return nil
 }
@@ -38,3 +38,28 @@ func (m *Storage) RemoveIncident(ctx context.Context, key string) error {
 
 	return nil
 }
+
+// ListIncidents will return every Incident saved inside the chosen storage mapped by its key
+func (m *Storage) ListIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	incidents := make(map[string]entity.Incident, len(m.Incidents))
+	for key, incident := range m.Incidents {
+		incidents[key] = copyIncident(incident)
+	}
+
+	return incidents, nil
+}
+
+// copyIncident will return the incident with its own threads so the caller modifying them does not change the storage outside the mutex
+func copyIncident(incident entity.Incident) entity.Incident {
+	if incident.Threads != nil {
+		incident.Threads = append([]entity.Thread{}, incident.Threads...)
+	}
+	if incident.PreviousThreads != nil {
+		incident.PreviousThreads = append([]entity.Thread{}, incident.PreviousThreads...)
+	}
+
+	return incident
+}
@@ -2,6 +2,8 @@ package gmap
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/alvintzz/alert-thread/internal/entity"
@@ -72,3 +74,58 @@ func TestRemoveIncident(t *testing.T) {
 		t.Errorf(messageNotError, flowRemoveIncident, "incident_1", err)
 	}
 }
+
+var flowListIncidents = "list incidents flow"
+
+func TestListIncidents(t *testing.T) {
+	storage, _ := NewStorage()
+	storage.Incidents["incident_1"] = entity.Incident{Title: "Incident 1"}
+	storage.Incidents["incident_2"] = entity.Incident{Title: "Incident 2"}
+
+	ctx := context.Background()
+	incidents, err := storage.ListIncidents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListIncidents, "all", err)
+	} else if len(incidents) != 2 || incidents["incident_2"].Title != "Incident 2" {
+		t.Errorf(messageNotExpect, flowListIncidents, "all", 2, incidents)
+	}
+
+	// Returned map is a copy so the caller can not modify the storage
+	delete(incidents, "incident_1")
+	if _, ok := storage.Incidents["incident_1"]; !ok {
+		t.Errorf(messageNotExpect, flowListIncidents, "copy", "incident_1 exists", "removed")
+	}
+}
+
+func TestIncidentThreadsCopy(t *testing.T) {
+	storage, _ := NewStorage()
+	ctx := context.Background()
+	storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1", Threads: []entity.Thread{{Channel: "C1", ThreadID: "1"}}})
+
+	// Worker updates threads of the incident while the incidents are listed, which must not race when run with -race
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			incident, _ := storage.GetIncident(ctx, "incident_1")
+			incident.Threads[0].Summary = fmt.Sprintf("summary %d", i)
+			storage.RegisterIncident(ctx, "incident_1", incident)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			incidents, _ := storage.ListIncidents(ctx)
+			_ = incidents["incident_1"].Threads[0].Summary
+		}
+	}()
+	wg.Wait()
+
+	// Thread modified by the caller is only saved when the incident is registered
+	incident, _ := storage.GetIncident(ctx, "incident_1")
+	incident.Threads[0].Summary = "unsaved"
+	if stored, _ := storage.GetIncident(ctx, "incident_1"); stored.Threads[0].Summary != "summary 99" {
+		t.Errorf(messageNotExpect, flowGetIncident, "copy of threads", "summary 99", stored.Threads[0].Summary)
+	}
+}
@@ -0,0 +1,44 @@
+package gmap
+
+import (
+	"context"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// StoreMute will save or update the mute inside the chosen storage
+func (m *Storage) StoreMute(ctx context.Context, mute entity.Mute) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	m.Mutes[mute.ID] = mute
+
+	return nil
+}
+
+// ListMutes will return every mute saved inside the chosen storage ordered by the creation time
+func (m *Storage) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	mutes := make([]entity.Mute, 0, len(m.Mutes))
+	for _, mute := range m.Mutes {
+		mutes = append(mutes, mute)
+	}
+	sort.Slice(mutes, func(i, j int) bool {
+		return mutes[i].CreatedAt.Before(mutes[j].CreatedAt)
+	})
+
+	return mutes, nil
+}
+
+// RemoveMute will remove the mute saved inside the chosen storage
+func (m *Storage) RemoveMute(ctx context.Context, id string) error {
+	m.Mutex.Lock()
+	defer m.Mutex.Unlock()
+
+	delete(m.Mutes, id)
+
+	return nil
+}
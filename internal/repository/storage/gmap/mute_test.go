@@ -0,0 +1,46 @@
+package gmap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var flowMute = "mute flow"
+
+func TestMute(t *testing.T) {
+	storage, _ := NewStorage()
+
+	ctx := context.Background()
+	now := time.Now()
+	for _, mute := range []entity.Mute{
+		{ID: "mute_1", Pattern: "db-*", CreatedAt: now, Until: now.Add(time.Hour)},
+		{ID: "mute_2", Pattern: "cpu-*", CreatedAt: now.Add(-time.Minute), Until: now.Add(time.Hour)},
+	} {
+		err := storage.StoreMute(ctx, mute)
+		if err != nil {
+			t.Errorf(messageNotError, flowMute, mute.ID, err)
+		}
+	}
+
+	mutes, err := storage.ListMutes(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowMute, "list", err)
+	} else if len(mutes) != 2 || mutes[0].ID != "mute_2" || mutes[1].Pattern != "db-*" {
+		t.Errorf(messageNotExpect, flowMute, "list", "[mute_2 mute_1]", mutes)
+	}
+
+	err = storage.RemoveMute(ctx, "mute_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowMute, "mute_2", err)
+	}
+
+	mutes, err = storage.ListMutes(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowMute, "list", err)
+	} else if len(mutes) != 1 || mutes[0].ID != "mute_1" {
+		t.Errorf(messageNotExpect, flowMute, "list", "[mute_1]", mutes)
+	}
+}
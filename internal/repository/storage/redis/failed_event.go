@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	rd "github.com/go-redis/redis/v8"
+)
+
+// StoreFailedEvent will save or update the failed event inside the chosen storage so it is not lost. Failed event never expires
+func (r *Storage) StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return r.client.HSet(ctx, r.failedEventKey(), event.ID, value).Err()
+}
+
+// GetFailedEvent will return the failed event saved inside the chosen storage. Empty object is returned when it is not found
+func (r *Storage) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	value, err := r.client.HGet(ctx, r.failedEventKey(), id).Bytes()
+	if err == rd.Nil {
+		return entity.FailedEvent{}, nil
+	} else if err != nil {
+		return entity.FailedEvent{}, err
+	}
+
+	event := entity.FailedEvent{}
+	err = json.Unmarshal(value, &event)
+	if err != nil {
+		return entity.FailedEvent{}, err
+	}
+
+	return event, nil
+}
+
+// ListFailedEvents will return every failed event saved inside the chosen storage ordered by the failure time
+func (r *Storage) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	values, err := r.client.HGetAll(ctx, r.failedEventKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]entity.FailedEvent, 0, len(values))
+	for _, value := range values {
+		event := entity.FailedEvent{}
+		err = json.Unmarshal([]byte(value), &event)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].FailedAt.Before(events[j].FailedAt)
+	})
+
+	return events, nil
+}
+
+// RemoveFailedEvent will remove the failed event saved inside the chosen storage
+func (r *Storage) RemoveFailedEvent(ctx context.Context, id string) error {
+	return r.client.HDel(ctx, r.failedEventKey(), id).Err()
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var flowStoreFailedEvent = "store failed event flow"
+var flowGetFailedEvent = "get failed event flow"
+var flowListFailedEvents = "list failed events flow"
+var flowRemoveFailedEvent = "remove failed event flow"
+
+func TestStoreFailedEvent(t *testing.T) {
+	storage, server := newTestStorage(t, time.Hour)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	err := storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", Key: "incident_1"})
+	if err != nil {
+		t.Errorf(messageNotError, flowStoreFailedEvent, "event_1", err)
+	}
+
+	value := server.HGet("test:failed_events", "event_1")
+	if !strings.Contains(value, `"key":"incident_1"`) {
+		t.Errorf(messageNotExpect, flowStoreFailedEvent, "event_1", "incident_1", value)
+	}
+	if ttl := server.TTL("test:failed_events"); ttl != 0 {
+		t.Errorf(messageNotExpect, flowStoreFailedEvent, "event_1", 0, ttl)
+	}
+}
+
+func TestGetFailedEvent(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", Key: "incident_1"})
+	server.HSet("test:failed_events", "event_3", "{")
+
+	event, err := storage.GetFailedEvent(ctx, "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetFailedEvent, "event_1", err)
+	} else if event.Key != "incident_1" {
+		t.Errorf(messageNotExpect, flowGetFailedEvent, "event_1", "incident_1", event.Key)
+	}
+
+	event, err = storage.GetFailedEvent(ctx, "event_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetFailedEvent, "event_2", err)
+	} else if event.ID != "" {
+		t.Errorf(messageNotExpect, flowGetFailedEvent, "event_2", "", event.ID)
+	}
+
+	_, err = storage.GetFailedEvent(ctx, "event_3")
+	if err == nil {
+		t.Errorf(messageNotExpect, flowGetFailedEvent, "event_3", "error", err)
+	}
+}
+
+func TestListFailedEvents(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1", FailedAt: now})
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_2", FailedAt: now.Add(-time.Minute)})
+
+	events, err := storage.ListFailedEvents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListFailedEvents, "all", err)
+	} else if len(events) != 2 || events[0].ID != "event_2" || events[1].ID != "event_1" {
+		t.Errorf(messageNotExpect, flowListFailedEvents, "all", "[event_2 event_1]", events)
+	}
+}
+
+func TestRemoveFailedEvent(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_1"})
+
+	err := storage.RemoveFailedEvent(ctx, "event_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveFailedEvent, "event_1", err)
+	} else if event, _ := storage.GetFailedEvent(ctx, "event_1"); event.ID != "" {
+		t.Errorf(messageNotExpect, flowRemoveFailedEvent, "event_1", "", event.ID)
+	}
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	rd "github.com/go-redis/redis/v8"
+)
+
+// GetIncident will return object of Incident saved inside the chosen storage
+func (r *Storage) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
+	value, err := r.client.Get(ctx, r.incidentKey(key)).Bytes()
+	if err == rd.Nil {
+		return entity.Incident{}, nil
+	} else if err != nil {
+		return entity.Incident{}, err
+	}
+
+	incident := entity.Incident{}
+	err = json.Unmarshal(value, &incident)
+	if err != nil {
+		return entity.Incident{}, err
+	}
+
+	return incident, nil
+}
+
+// RegisterIncident will register or update object of Incident saved inside the chosen storage. The incident expiration is refreshed on every update
+func (r *Storage) RegisterIncident(ctx context.Context, key string, incident entity.Incident) error {
+	value, err := json.Marshal(incident)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.incidentKey(key), value, r.ttl).Err()
+}
+
+// RemoveIncident will remove object of Incident saved inside the chosen storage
+func (r *Storage) RemoveIncident(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.incidentKey(key)).Err()
+}
+
+// ListIncidents will return every Incident saved inside the chosen storage mapped by its key.
+// Keys are scanned incrementally so Redis is not blocked by large number of incidents
+func (r *Storage) ListIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	prefix := r.incidentKey("")
+	incidents := map[string]entity.Incident{}
+
+	iter := r.client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		value, err := r.client.Get(ctx, iter.Val()).Bytes()
+		if err == rd.Nil {
+			// Incident is expired or removed after it is scanned
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		incident := entity.Incident{}
+		err = json.Unmarshal(value, &incident)
+		if err != nil {
+			return nil, err
+		}
+		incidents[strings.TrimPrefix(iter.Val(), prefix)] = incident
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+var messageFailedObj = "Failed to create storage object: %s"
+var messageNotError = "Failed in %s for %s. Not expecting error %s"
+var messageNotExpect = "Failed in %s for %s. Result is not matched expected \"%+v\", got \"%+v\""
+
+var flowGetIncident = "get incident flow"
+var flowRegisterIncident = "register incident flow"
+var flowRemoveIncident = "remove incident flow"
+
+func newTestStorage(t *testing.T, ttl time.Duration) (*Storage, *miniredis.Miniredis) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	storage, err := NewStorage(Options{Address: server.Addr(), Prefix: "test:", TTL: ttl, LockTTL: time.Second})
+	if err != nil {
+		server.Close()
+		t.Fatalf(messageFailedObj, err)
+	}
+
+	return storage, server
+}
+
+func TestNewStorage(t *testing.T) {
+	server, _ := miniredis.Run()
+	address := server.Addr()
+	server.Close()
+
+	_, err := NewStorage(Options{Address: address})
+	if err == nil {
+		t.Errorf(messageNotExpect, "new storage flow", address, "error", err)
+	}
+}
+
+func TestGetIncident(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	server.Set("test:incident:incident_1", `{"title": "Incident 1"}`)
+	server.Set("test:incident:incident_3", `{`)
+
+	ctx := context.Background()
+	incident, err := storage.GetIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetIncident, "incident_1", err)
+	} else if incident.Title != "Incident 1" {
+		t.Errorf(messageNotExpect, flowGetIncident, "incident_1", "Incident 1", incident.Title)
+	}
+
+	incident, err = storage.GetIncident(ctx, "incident_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowGetIncident, "incident_2", err)
+	} else if incident.Title != "" {
+		t.Errorf(messageNotExpect, flowGetIncident, "incident_2", "", incident.Title)
+	}
+
+	_, err = storage.GetIncident(ctx, "incident_3")
+	if err == nil {
+		t.Errorf(messageNotExpect, flowGetIncident, "incident_3", "error", err)
+	}
+}
+
+func TestRegisterIncident(t *testing.T) {
+	storage, server := newTestStorage(t, time.Hour)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	err := storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1", Status: entity.StatusWarning})
+	if err != nil {
+		t.Errorf(messageNotError, flowRegisterIncident, "incident_1", err)
+	}
+	if ttl := server.TTL("test:incident:incident_1"); ttl != time.Hour {
+		t.Errorf(messageNotExpect, flowRegisterIncident, "incident_1", time.Hour, ttl)
+	}
+
+	incident, _ := storage.GetIncident(ctx, "incident_1")
+	if incident.Title != "Incident 1" || incident.Status != entity.StatusWarning {
+		t.Errorf(messageNotExpect, flowRegisterIncident, "incident_1", "Incident 1", incident)
+	}
+
+	server.FastForward(2 * time.Hour)
+	incident, _ = storage.GetIncident(ctx, "incident_1")
+	if incident.Title != "" {
+		t.Errorf(messageNotExpect, flowRegisterIncident, "expired incident_1", "", incident.Title)
+	}
+}
+
+func TestRemoveIncident(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	storage.RegisterIncident(ctx, "incident_1", entity.Incident{Title: "Incident 1"})
+
+	err := storage.RemoveIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveIncident, "incident_1", err)
+	} else if server.Exists("test:incident:incident_1") {
+		t.Errorf(messageNotExpect, flowRemoveIncident, "incident_1", "removed", "exists")
+	}
+
+	err = storage.RemoveIncident(ctx, "incident_1")
+	if err != nil {
+		t.Errorf(messageNotError, flowRemoveIncident, "incident_1", err)
+	}
+}
+
+var flowListIncidents = "list incidents flow"
+
+func TestListIncidents(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 150; i++ {
+		storage.RegisterIncident(ctx, fmt.Sprintf("incident_%d", i), entity.Incident{Title: fmt.Sprintf("Incident %d", i)})
+	}
+	server.Set("test:lock:incident_1", "token")
+	server.Set("other:incident:incident_1", "{}")
+
+	incidents, err := storage.ListIncidents(ctx)
+	if err != nil {
+		t.Errorf(messageNotError, flowListIncidents, "all", err)
+	} else if len(incidents) != 150 || incidents["incident_42"].Title != "Incident 42" {
+		t.Errorf(messageNotExpect, flowListIncidents, "all", 150, len(incidents))
+	}
+}
@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rd "github.com/go-redis/redis/v8"
+)
+
+// Options defines connection and behaviour of Redis storage
+type Options struct {
+	Address  string
+	Password string
+	Database int
+
+	// Prefix is prepended to every key so multiple deployments can share one Redis
+	Prefix string
+
+	// TTL is expiration of an incident since its last update. Zero means the incident never expires
+	TTL time.Duration
+
+	// LockTTL is how long the lock of a replica which stops renewing it, e.g. crashed, is kept before it is released automatically
+	LockTTL time.Duration
+}
+
+// Storage is object Storage using Redis shared by every service replica
+type Storage struct {
+	client  rd.UniversalClient
+	prefix  string
+	ttl     time.Duration
+	lockTTL time.Duration
+}
+
+const defaultLockTTL = 30 * time.Second
+
+// NewStorage will return storage implementation using Redis
+func NewStorage(options Options) (*Storage, error) {
+	client := rd.NewClient(&rd.Options{
+		Addr:     options.Address,
+		Password: options.Password,
+		DB:       options.Database,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := client.Ping(ctx).Err()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("Failed to connect redis %s because %s", options.Address, err)
+	}
+
+	lockTTL := options.LockTTL
+	if lockTTL <= 0 {
+		lockTTL = defaultLockTTL
+	}
+
+	return &Storage{
+		client:  client,
+		prefix:  options.Prefix,
+		ttl:     options.TTL,
+		lockTTL: lockTTL,
+	}, nil
+}
+
+// Close will close every connection to Redis
+func (r *Storage) Close() error {
+	return r.client.Close()
+}
+
+func (r *Storage) incidentKey(key string) string {
+	return r.prefix + "incident:" + key
+}
+
+func (r *Storage) failedEventKey() string {
+	return r.prefix + "failed_events"
+}
+
+func (r *Storage) muteKey() string {
+	return r.prefix + "mutes"
+}
+
+func (r *Storage) lockKey(key string) string {
+	return r.prefix + "lock:" + key
+}
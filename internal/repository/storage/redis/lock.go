@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	rd "github.com/go-redis/redis/v8"
+	log "github.com/sirupsen/logrus"
+)
+
+// lockRetryInterval is the waiting time before trying to acquire a lock owned by another replica
+const lockRetryInterval = 50 * time.Millisecond
+
+// renewScript only extends the lock when it is still owned by the same token
+var renewScript = rd.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// unlockScript only release the lock when it is still owned by the same token
+var unlockScript = rd.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// Lock will wait until the incident is not locked by any other replica and lock it. The lock is renewed until the returned function is called to release it,
+// so slow notification does not let another replica process the same incident
+func (r *Storage) Lock(ctx context.Context, key string) (func(), error) {
+	token := make([]byte, 16)
+	_, err := rand.Read(token)
+	if err != nil {
+		return nil, err
+	}
+	value := hex.EncodeToString(token)
+
+	ctx, cancel := context.WithTimeout(ctx, r.lockTTL)
+	defer cancel()
+
+	ticker := time.NewTicker(lockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := r.client.SetNX(ctx, r.lockKey(key), value, r.lockTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	done := make(chan struct{})
+	go r.renew(key, value, done)
+
+	unlock := func() {
+		close(done)
+		err := unlockScript.Run(context.Background(), r.client, []string{r.lockKey(key)}, value).Err()
+		if err != nil {
+			log.Errorf("Failed to release lock of %s because %s", key, err)
+		}
+	}
+
+	return unlock, nil
+}
+
+// renew will extend the lock every third of its TTL until it is released or owned by another replica
+func (r *Storage) renew(key, value string, done chan struct{}) {
+	ticker := time.NewTicker(r.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		renewed, err := renewScript.Run(context.Background(), r.client, []string{r.lockKey(key)}, value, r.lockTTL.Milliseconds()).Int()
+		if err != nil {
+			log.Warnf("Failed to renew lock of %s because %s", key, err)
+			continue
+		}
+		if renewed == 0 {
+			log.Warnf("Lock of %s is lost before it is released", key)
+			return
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+var flowLock = "lock flow"
+
+func TestLock(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	// Second storage act as another replica sharing the same Redis
+	replica, err := NewStorage(Options{Address: server.Addr(), Prefix: "test:", LockTTL: time.Second})
+	if err != nil {
+		t.Fatalf(messageFailedObj, err)
+	}
+	defer replica.Close()
+
+	ctx := context.Background()
+	unlock, err := storage.Lock(ctx, "incident_1")
+	if err != nil {
+		t.Fatalf(messageNotError, flowLock, "incident_1", err)
+	}
+
+	// Other key must not be blocked
+	unlockOther, err := replica.Lock(ctx, "incident_2")
+	if err != nil {
+		t.Errorf(messageNotError, flowLock, "incident_2", err)
+	} else {
+		unlockOther()
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = replica.Lock(timeout, "incident_1")
+	if err == nil {
+		t.Errorf(messageNotExpect, flowLock, "locked incident_1", "error", err)
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		unlockReplica, err := replica.Lock(ctx, "incident_1")
+		if err == nil {
+			unlockReplica()
+		}
+		close(acquired)
+	}()
+
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Errorf(messageNotExpect, flowLock, "released incident_1", "acquired", "timeout")
+	}
+}
+
+func TestLockConcurrent(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := storage.Lock(context.Background(), "incident_1")
+			if err != nil {
+				t.Errorf(messageNotError, flowLock, "incident_1", err)
+				return
+			}
+			defer unlock()
+
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if maxRunning != 1 {
+		t.Errorf(messageNotExpect, flowLock, "concurrent incident_1", 1, maxRunning)
+	}
+}
+
+func TestLockRenew(t *testing.T) {
+	storage, server := newTestStorage(t, 0)
+	defer server.Close()
+	defer storage.Close()
+
+	unlock, err := storage.Lock(context.Background(), "incident_1")
+	if err != nil {
+		t.Fatalf(messageNotError, flowLock, "incident_1", err)
+	}
+
+	// Lock held longer than its TTL is renewed so it is not released to another replica
+	server.FastForward(800 * time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	if ttl := server.TTL("test:lock:incident_1"); ttl <= 200*time.Millisecond {
+		t.Errorf(messageNotExpect, flowLock, "renewed incident_1", "renewed TTL", ttl)
+	}
+
+	unlock()
+	if server.Exists("test:lock:incident_1") {
+		t.Errorf(messageNotExpect, flowLock, "released incident_1", "removed lock", "existing lock")
+	}
+}
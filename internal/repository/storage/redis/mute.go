@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// StoreMute will save or update the mute inside the chosen storage. Expired mute is removed by the usecase instead of Redis TTL
+func (r *Storage) StoreMute(ctx context.Context, mute entity.Mute) error {
+	value, err := json.Marshal(mute)
+	if err != nil {
+		return err
+	}
+
+	return r.client.HSet(ctx, r.muteKey(), mute.ID, value).Err()
+}
+
+// ListMutes will return every mute saved inside the chosen storage ordered by the creation time
+func (r *Storage) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	values, err := r.client.HGetAll(ctx, r.muteKey()).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	mutes := make([]entity.Mute, 0, len(values))
+	for _, value := range values {
+		mute := entity.Mute{}
+		err = json.Unmarshal([]byte(value), &mute)
+		if err != nil {
+			return nil, err
+		}
+		mutes = append(mutes, mute)
+	}
+	sort.Slice(mutes, func(i, j int) bool {
+		return mutes[i].CreatedAt.Before(mutes[j].CreatedAt)
+	})
+
+	return mutes, nil
+}
+
+// RemoveMute will remove the mute saved inside the chosen storage
+func (r *Storage) RemoveMute(ctx context.Context, id string) error {
+	return r.client.HDel(ctx, r.muteKey(), id).Err()
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// isDuplicate will check whether the notification is identical with the last reply of the incident and still inside the dedup window.
+// Notification resolved to a channel which is not notified yet is never a duplicate
+func (u *Usecase) isDuplicate(incident entity.Incident, channels []string, param entity.ReplyInThread) bool {
+	if u.dedupWindow <= 0 || len(incident.Threads) == 0 {
+		return false
+	}
+
+	for _, channel := range channels {
+		if _, ok := incident.GetThread(channel); !ok {
+			return false
+		}
+	}
+
+	return incident.Status == param.GetStatus() &&
+		incident.DetailHash == detailHash(param) &&
+		time.Since(incident.LastReply) < u.dedupWindow
+}
+
+// detailHash will return hash of the thread reply content of the notification. Image is not hashed as vendor like Datadog sends new snapshot
+// on every re-notification, and getting it might wait for the upload
+func detailHash(param entity.ReplyInThread) string {
+	hash := sha256.Sum256([]byte(param.GetStatus().Code + "\n" + param.GetDetail()))
+	return hex.EncodeToString(hash[:])
+}
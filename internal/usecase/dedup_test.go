@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+type detailParameter struct {
+	cycleParameter
+	detail string
+}
+
+func (p *detailParameter) GetDetail() string {
+	return p.detail
+}
+
+// snapshotParameter returns new image on every call like Datadog snapshot
+type snapshotParameter struct {
+	detailParameter
+	images int
+}
+
+func (p *snapshotParameter) GetImage() string {
+	p.images++
+	return fmt.Sprintf("http://snapshot/%d.png", p.images)
+}
+
+func TestReplyInThreadDedup(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{DedupWindow: time.Hour})
+
+	steps := []*detailParameter{
+		{cycleParameter: cycleParameter{key: "dedup_key", status: entity.StatusTriggered}, detail: "CPU 95%"},
+		{cycleParameter: cycleParameter{key: "dedup_key", status: entity.StatusTriggered}, detail: "CPU 95%"},
+		{cycleParameter: cycleParameter{key: "dedup_key", status: entity.StatusTriggered}, detail: "CPU 95%"},
+		{cycleParameter: cycleParameter{key: "dedup_key", status: entity.StatusTriggered}, detail: "CPU 99%"},
+		{cycleParameter: cycleParameter{key: "dedup_key", status: entity.StatusRecovered}, detail: "CPU 99%"},
+	}
+	expectedReplies := []int{1, 1, 1, 2, 3}
+	expectedRepeats := []int{0, 1, 2, 0, 0}
+
+	for k, param := range steps {
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Dedup reply %d is not expecting error %s", k, err)
+		}
+
+		replies := len(notif.sent) - len(notif.parents())
+		if replies != expectedReplies[k] {
+			t.Errorf("Dedup reply %d expecting %d replies, got %d instead", k, expectedReplies[k], replies)
+		}
+
+		incident, _ := storage.GetIncident(ctx, "dedup_key")
+		if incident.Repeats != expectedRepeats[k] {
+			t.Errorf("Dedup reply %d expecting %d repeats, got %d instead", k, expectedRepeats[k], incident.Repeats)
+		}
+	}
+
+	if !strings.Contains(notif.updated[1].Message, "×2") {
+		t.Errorf("Repeated notification expecting counter in parent message, got %s instead", notif.updated[1].Message)
+	}
+	if strings.Contains(notif.updated[2].Message, "×") {
+		t.Errorf("Changed notification expecting counter to be removed from parent message, got %s instead", notif.updated[2].Message)
+	}
+
+	// Identical notification after the window is replied again
+	incident, _ := storage.GetIncident(ctx, "dedup_key")
+	incident.LastReply = time.Now().Add(-2 * time.Hour)
+	storage.RegisterIncident(ctx, "dedup_key", incident)
+
+	err := uc.ReplyInThread(ctx, steps[4])
+	if err != nil {
+		t.Fatalf("Reply after dedup window is not expecting error %s", err)
+	}
+	if replies := len(notif.sent) - len(notif.parents()); replies != 4 {
+		t.Errorf("Reply after dedup window expecting %d replies, got %d instead", 4, replies)
+	}
+
+	// Deduplication is disabled without window
+	disabled := New(newMemoryStorage(), &recorderNotification{}, Options{})
+	for k := 0; k < 2; k++ {
+		disabled.ReplyInThread(ctx, steps[0])
+	}
+	if incident, _ := disabled.storage.GetIncident(ctx, "dedup_key"); incident.Repeats != 0 {
+		t.Errorf("Disabled dedup expecting no repeat, got %d instead", incident.Repeats)
+	}
+}
+
+func TestReplayFailedEventDedup(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &brokenNotification{}
+	uc := New(storage, notif, Options{DedupWindow: time.Hour})
+
+	uc.ReplyInThread(ctx, &detailParameter{cycleParameter: cycleParameter{key: "replay_dedup_key", status: entity.StatusTriggered}, detail: "CPU 95%"})
+
+	// Reply failed during the outage while the incident already records its detail
+	notif.broken = "channel"
+	param := &detailParameter{cycleParameter: cycleParameter{key: "replay_dedup_key", status: entity.StatusTriggered}, detail: "CPU 99%"}
+	err := uc.ReplyInThread(ctx, param)
+	if err == nil {
+		t.Fatalf("Reply during outage expecting error, got nil instead")
+	}
+	events, _ := uc.ListFailedEvents(ctx)
+	if len(events) != 1 {
+		t.Fatalf("Reply during outage expecting 1 failed event, got %d instead", len(events))
+	}
+
+	// Replay inside the dedup window must still deliver the reply
+	notif.broken = ""
+	err = uc.ReplayFailedEvent(ctx, events[0], param)
+	if err != nil {
+		t.Fatalf("Replay is not expecting error %s", err)
+	}
+	last := notif.sent[len(notif.sent)-1]
+	if last.Message != "CPU 99%" || last.Metadata["timestamp"] == "" {
+		t.Errorf("Replay inside dedup window expecting the reply, got %+v instead", last)
+	}
+}
+
+func TestReplyInThreadDedupSnapshot(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	uc := New(storage, &recorderNotification{}, Options{DedupWindow: time.Hour})
+
+	param := &snapshotParameter{detailParameter: detailParameter{cycleParameter: cycleParameter{key: "snapshot_key", status: entity.StatusTriggered}, detail: "CPU 95%"}}
+	for k := 0; k < 2; k++ {
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Snapshot reply %d is not expecting error %s", k, err)
+		}
+	}
+
+	// Re-notification with new snapshot but identical detail is still a duplicate
+	if incident, _ := storage.GetIncident(ctx, "snapshot_key"); incident.Repeats != 1 {
+		t.Errorf("Snapshot dedup expecting 1 repeat, got %d instead", incident.Repeats)
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeliveryError is returned when the notification failed to be delivered to some channels. Delivery to the other channels is not affected by the failure
+type DeliveryError struct {
+	// Total is number of channels the notification is delivered to
+	Total int
+
+	// Failed is the error of every channel failed to be delivered
+	Failed map[string]error
+}
+
+func (e *DeliveryError) Error() string {
+	channels := failedChannels(e)
+
+	reasons := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		reasons = append(reasons, fmt.Sprintf("%s (%s)", channel, e.Failed[channel]))
+	}
+
+	return fmt.Sprintf("Failed to deliver to %d of %d channels: %s", len(e.Failed), e.Total, strings.Join(reasons, ", "))
+}
+
+// failedChannels will return the sorted channels failed to be delivered inside the error. Nil is returned when the error is not a partial delivery
+func failedChannels(err error) []string {
+	deliveryErr := &DeliveryError{}
+	if !errors.As(err, &deliveryErr) {
+		return nil
+	}
+
+	channels := make([]string, 0, len(deliveryErr.Failed))
+	for channel := range deliveryErr.Failed {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	return channels
+}
+
+// delivery collects the result of notification delivered to every channel so one failed channel does not stop the others
+type delivery struct {
+	channels map[string]bool
+	failed   map[string]error
+}
+
+func newDelivery() *delivery {
+	return &delivery{
+		channels: map[string]bool{},
+		failed:   map[string]error{},
+	}
+}
+
+// done will record the result of delivery to the channel. Channel is failed when any of its delivery is failed
+func (d *delivery) done(channel string, err error) {
+	d.channels[channel] = true
+	if err != nil && d.failed[channel] == nil {
+		d.failed[channel] = err
+	}
+}
+
+// err will return DeliveryError reporting the failed channels or nil when every channel is delivered
+func (d *delivery) err() error {
+	if len(d.failed) == 0 {
+		return nil
+	}
+
+	return &DeliveryError{Total: len(d.channels), Failed: d.failed}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// brokenNotification fails every message of the broken channel and records the others
+type brokenNotification struct {
+	recorderNotification
+	broken string
+}
+
+func (n *brokenNotification) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	if param.Channel == n.broken {
+		return "", errorDefault
+	}
+	return n.recorderNotification.SendMessage(ctx, param)
+}
+func (n *brokenNotification) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	if param.Channel == n.broken {
+		return errorDefault
+	}
+	return n.recorderNotification.UpdateMessage(ctx, param)
+}
+
+func TestReplyInThreadPartialDelivery(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &brokenNotification{broken: "email:oncall"}
+	router, _ := NewRouter(RoutingConfig{
+		Rules: []RoutingRule{{Vendor: "*", Channels: []string{"email:oncall", "slack:C1", "webhook:remediation"}}},
+	})
+	uc := New(storage, notif, Options{Router: router})
+
+	err := uc.ReplyInThread(ctx, &routeParameter{vendor: "*", status: entity.StatusTriggered})
+	deliveryErr, ok := err.(*DeliveryError)
+	if !ok {
+		t.Fatalf("Partial delivery expecting DeliveryError, got %v instead", err)
+	}
+	if deliveryErr.Total != 3 || len(deliveryErr.Failed) != 1 || deliveryErr.Failed["email:oncall"] == nil {
+		t.Errorf("Partial delivery expecting only email:oncall to fail out of 3 channels, got %+v instead", deliveryErr)
+	}
+	if !strings.Contains(err.Error(), "Failed to deliver to 1 of 3 channels: email:oncall") {
+		t.Errorf("Partial delivery expecting error to report the failed channel, got %s instead", err)
+	}
+
+	// Healthy destinations still get their threads and replies
+	incident, _ := storage.GetIncident(ctx, "route_key")
+	if len(incident.Threads) != 2 || incident.Threads[0].Channel != "slack:C1" || incident.Threads[1].Channel != "webhook:remediation" {
+		t.Errorf("Partial delivery expecting threads of healthy destinations, got %+v instead", incident.Threads)
+	}
+	if len(notif.sent) != 4 {
+		t.Errorf("Partial delivery expecting 2 parents and 2 replies, got %d messages instead", len(notif.sent))
+	}
+
+	// Recovered destination get its thread on the next notification while the others keep theirs
+	notif.broken = ""
+	err = uc.ReplyInThread(ctx, &routeParameter{vendor: "*", status: entity.StatusRecovered})
+	if err != nil {
+		t.Fatalf("Delivery after recovery is not expecting error %s", err)
+	}
+	incident, _ = storage.GetIncident(ctx, "route_key")
+	if len(incident.Threads) != 3 || incident.Threads[2].Channel != "email:oncall" {
+		t.Errorf("Delivery after recovery expecting a thread per destination, got %+v instead", incident.Threads)
+	}
+}
+
+func TestReplayPartialDelivery(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &brokenNotification{broken: "email:oncall"}
+	router, _ := NewRouter(RoutingConfig{
+		Rules: []RoutingRule{{Vendor: "*", Channels: []string{"email:oncall", "slack:C1"}}},
+	})
+	uc := New(storage, notif, Options{Router: router})
+
+	param := &routeParameter{vendor: "*", status: entity.StatusTriggered}
+	uc.ReplyInThread(ctx, param)
+
+	events, _ := uc.ListFailedEvents(ctx)
+	if len(events) != 1 || strings.Join(events[0].Channels, ",") != "email:oncall" {
+		t.Fatalf("Partial delivery expecting failed event of email:oncall only, got %+v instead", events)
+	}
+
+	// Replay only delivers into the failed channel so the healthy one does not get the reply twice
+	notif.broken = ""
+	notif.sent = nil
+	err := uc.ReplayFailedEvent(ctx, events[0], param)
+	if err != nil {
+		t.Fatalf("Replay of partial delivery is not expecting error %s", err)
+	}
+	for _, message := range notif.sent {
+		if message.Channel != "email:oncall" {
+			t.Errorf("Replay of partial delivery expecting only email:oncall, got message to %s instead", message.Channel)
+		}
+	}
+	if len(notif.sent) != 2 {
+		t.Errorf("Replay of partial delivery expecting parent and reply, got %d messages instead", len(notif.sent))
+	}
+	if incident, _ := storage.GetIncident(ctx, "route_key"); len(incident.Threads) != 2 {
+		t.Errorf("Replay of partial delivery expecting thread of every channel, got %+v instead", incident.Threads)
+	}
+}
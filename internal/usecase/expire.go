@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ExpireIncidents will remove every incident which is not updated longer than the stale TTL and archive incident closed longer than the reopen window.
+// Threads of stale incident which is not recovered yet get auto-closing reply before removal when it is enabled. Expired mutes are removed as well
+func (u *Usecase) ExpireIncidents(ctx context.Context) error {
+	u.expireMutes(ctx)
+
+	incidents, err := u.storage.ListIncidents(ctx)
+	if err != nil {
+		log.Errorf("Failed to list incidents from storage because %s", err)
+		return fmt.Errorf("Failed to list incidents from storage because %s", err)
+	}
+
+	removed := 0
+	for key, incident := range incidents {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !u.isExpired(incident, time.Now()) {
+			continue
+		}
+
+		err = u.expireIncident(ctx, key)
+		if err != nil {
+			log.Errorf("Failed to expire incident %s because %s", key, err)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		log.Infof("Expired %d of %d incidents", removed, len(incidents))
+	}
+
+	return nil
+}
+
+// isExpired will check whether the incident can be removed or archived from the storage
+func (u *Usecase) isExpired(incident entity.Incident, now time.Time) bool {
+	if u.state(incident, now) == stateExpired {
+		return true
+	}
+
+	return u.isStale(incident, now)
+}
+
+// archive will return the expired incident keeping only its threads so the incident triggered again with the same key links to them.
+// Archive is removed once it is stale
+func archive(incident entity.Incident) entity.Incident {
+	return entity.Incident{
+		PreviousThreads: incident.Threads,
+		LastUpdate:      incident.LastUpdate,
+	}
+}
+
+// isStale will check whether the incident is not updated longer than the stale TTL
+func (u *Usecase) isStale(incident entity.Incident, now time.Time) bool {
+	return u.staleTTL > 0 && now.Sub(incident.LastUpdate) > u.staleTTL
+}
+
+// expireIncident will remove the stale incident or archive the closed one after checking it is still expired as it might be updated since it is listed
+func (u *Usecase) expireIncident(ctx context.Context, key string) error {
+	unlock, err := u.lockIncident(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	incident, err := u.storage.GetIncident(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !u.isExpired(incident, now) {
+		return nil
+	}
+	if !u.isStale(incident, now) {
+		return u.storage.RegisterIncident(ctx, key, archive(incident))
+	}
+
+	if u.autoClose && !incident.Status.IsRecovered() {
+		message := fmt.Sprintf("No updates for %s, auto-closing", formatDuration(u.staleTTL))
+		for _, thread := range incident.Threads {
+			_, err = u.notification.SendMessage(ctx, entity.Notification{
+				Key:     key,
+				Channel: thread.Channel,
+				Title:   threadTitle(incident, thread),
+				Message: message,
+				Color:   incident.Status.Color,
+				Metadata: map[string]string{
+					"timestamp": thread.ThreadID,
+				},
+			})
+			if err != nil {
+				// Incident is still removed so unreachable thread does not keep it forever
+				log.Warnf("Failed to send auto-closing reply of incident %s because %s", key, err)
+			}
+		}
+	}
+
+	return u.storage.RemoveIncident(ctx, key)
+}
+
+// formatDuration will return the duration in whole hours or minutes when possible
+func formatDuration(duration time.Duration) string {
+	count, unit := int64(0), ""
+	switch {
+	case duration%time.Hour == 0:
+		count, unit = int64(duration/time.Hour), "hour"
+	case duration%time.Minute == 0:
+		count, unit = int64(duration/time.Minute), "minute"
+	default:
+		return duration.String()
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, unit)
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
+}
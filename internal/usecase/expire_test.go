@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+func TestExpireIncidents(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{GracePeriod: time.Minute, ReopenWindow: time.Hour, StaleTTL: 24 * time.Hour, AutoCloseReply: true})
+
+	now := time.Now()
+	threads := []entity.Thread{{Channel: "C1", ThreadID: "thread_1"}, {Channel: "C2", ThreadID: "thread_2"}}
+	storage.incidents = map[string]entity.Incident{
+		"fresh":           {Threads: threads, Status: entity.StatusTriggered, LastUpdate: now},
+		"stale":           {Threads: threads, Status: entity.StatusTriggered, LastUpdate: now.Add(-25 * time.Hour)},
+		"stale_recovered": {Threads: threads, Status: entity.StatusRecovered, LastUpdate: now.Add(-25 * time.Hour), ResolvedAt: now.Add(-25 * time.Hour)},
+		"closed":          {Threads: threads, Status: entity.StatusRecovered, LastUpdate: now.Add(-30 * time.Minute), ResolvedAt: now.Add(-30 * time.Minute)},
+		"expired":         {Threads: threads, Status: entity.StatusRecovered, LastUpdate: now.Add(-2 * time.Hour), ResolvedAt: now.Add(-2 * time.Hour)},
+	}
+
+	err := uc.ExpireIncidents(ctx)
+	if err != nil {
+		t.Fatalf("Expire incidents is not expecting error %s", err)
+	}
+
+	expected := map[string]bool{"fresh": true, "stale": false, "stale_recovered": false, "closed": true, "expired": true}
+	for key, exist := range expected {
+		if _, ok := storage.incidents[key]; ok != exist {
+			t.Errorf("Incident %s expecting to exist %t, got %t instead", key, exist, ok)
+		}
+	}
+
+	// Only the stale incident which is not recovered get auto-closing reply in each of its threads
+	if len(notif.sent) != 2 {
+		t.Fatalf("Expire incidents expecting 2 auto-closing replies, got %d instead", len(notif.sent))
+	}
+	for k, message := range notif.sent {
+		if message.Key != "stale" || message.Metadata["timestamp"] != threads[k].ThreadID || !strings.Contains(message.Message, "No updates for 24 hours") {
+			t.Errorf("Auto-closing reply %d is not expected, got %+v instead", k, message)
+		}
+	}
+
+	// Expired incident is archived with only its threads so it can be linked when triggered again
+	archived := storage.incidents["expired"]
+	if len(archived.Threads) != 0 || len(archived.PreviousThreads) != 2 || !archived.ResolvedAt.IsZero() {
+		t.Errorf("Expired incident expecting to be archived, got %+v instead", archived)
+	}
+	if open, _ := uc.ListOpenIncidents(ctx); len(open) != 1 {
+		t.Errorf("Archived incident expecting not to be listed as open, got %d open incidents instead", len(open))
+	}
+	err = uc.ReplyInThread(ctx, &cycleParameter{key: "expired", status: entity.StatusTriggered})
+	if err != nil {
+		t.Fatalf("Reply of archived incident is not expecting error %s", err)
+	}
+	if parent := notif.parents()[0]; !strings.Contains(parent.Message, "Reopened From:\nthread thread_1 in C1\nthread thread_2 in C2") {
+		t.Errorf("Reply of archived incident expecting links of previous threads, got %s instead", parent.Message)
+	}
+
+	// Archive is removed once it is stale
+	archived.LastUpdate = now.Add(-25 * time.Hour)
+	storage.incidents["archived"] = archived
+	uc.ExpireIncidents(ctx)
+	if _, ok := storage.incidents["archived"]; ok {
+		t.Errorf("Stale archive expecting to be removed")
+	}
+
+	// Listing failure is reported
+	failed := New(&storageMock{}, notif, Options{StaleTTL: time.Hour})
+	if err := failed.ExpireIncidents(ctx); err == nil {
+		t.Errorf("Expire incidents expecting error when storage can not be listed")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	durations := []time.Duration{time.Hour, 48 * time.Hour, 90 * time.Minute, time.Minute, 90 * time.Second}
+	expected := []string{"1 hour", "48 hours", "90 minutes", "1 minute", "1m30s"}
+
+	for k, duration := range durations {
+		if value := formatDuration(duration); value != expected[k] {
+			t.Errorf("Duration %d expecting %s, got %s instead", k, expected[k], value)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ListFailedEvents will return every vendor notification failed to be processed
+func (u *Usecase) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	return u.storage.ListFailedEvents(ctx)
+}
+
+// GetFailedEvent will return the failed event of the id. Empty object is returned when it is not found
+func (u *Usecase) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	return u.storage.GetFailedEvent(ctx, id)
+}
+
+// ReplayFailedEvent will process the vendor notification of the failed event again, only into its failed channels when it is partially delivered.
+// The failed event is removed when succeed, otherwise its error and failed channels are updated
+func (u *Usecase) ReplayFailedEvent(ctx context.Context, event entity.FailedEvent, param entity.ReplyInThread) error {
+	err := u.replyInThread(ctx, param, &event)
+	if err != nil {
+		if channels := failedChannels(err); channels != nil {
+			event.Channels = channels
+		}
+		event.Error = err.Error()
+		event.Attempts++
+		event.FailedAt = time.Now()
+
+		storeErr := u.storage.StoreFailedEvent(ctx, event)
+		if storeErr != nil {
+			log.Errorf("Failed to update failed event %s because %s", event.ID, storeErr)
+		}
+		return err
+	}
+
+	err = u.storage.RemoveFailedEvent(ctx, event.ID)
+	if err != nil {
+		return fmt.Errorf("Failed to remove replayed event %s because %s", event.ID, err)
+	}
+
+	return nil
+}
+
+// DiscardFailedEvent will remove the failed event without processing it again
+func (u *Usecase) DiscardFailedEvent(ctx context.Context, id string) error {
+	return u.storage.RemoveFailedEvent(ctx, id)
+}
+
+// storeFailedEvent will save the vendor notification failed to be processed into storage so it is not silently dropped.
+// The raw webhook request is saved when it is kept by the notification, otherwise the notification itself is saved
+func (u *Usecase) storeFailedEvent(ctx context.Context, param entity.ReplyInThread, cause error) {
+	var err error
+	source := entity.Source{}
+	if sourced, ok := param.(entity.Sourced); ok {
+		source = sourced.GetSource()
+	}
+	if len(source.Payload) == 0 {
+		source.Payload, err = json.Marshal(param)
+		if err != nil {
+			log.Errorf("Failed to marshal payload of %s because %s", param.GetKey(), err)
+		}
+	}
+
+	event := entity.FailedEvent{
+		ID:       newEventID(),
+		Key:      param.GetKey(),
+		Vendor:   param.GetVendor(),
+		Webhook:  source.Webhook,
+		Query:    source.Query,
+		Payload:  source.Payload,
+		Error:    cause.Error(),
+		Attempts: 1,
+		FailedAt: time.Now(),
+		Channels: failedChannels(cause),
+	}
+
+	err = u.storage.StoreFailedEvent(ctx, event)
+	if err != nil {
+		log.Errorf("Failed to store failed event of %s because %s", param.GetKey(), err)
+	}
+}
+
+// newEventID will return random identifier of failed event
+func newEventID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+type payloadParameter struct {
+	cycleParameter
+	channel string
+}
+
+func (p *payloadParameter) GetChannel() string {
+	return p.channel
+}
+func (p *payloadParameter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"key": p.key})
+}
+
+type sourceParameter struct {
+	payloadParameter
+	entity.Source
+}
+
+func TestReplyInThreadFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	uc := New(storage, &notificationMock{}, Options{})
+
+	err := uc.ReplyInThread(ctx, &payloadParameter{cycleParameter: cycleParameter{key: "failed_key"}, channel: "failed_channel"})
+	if err == nil {
+		t.Errorf("Reply with failed notification expecting error, got nil instead")
+	}
+
+	events, _ := uc.ListFailedEvents(ctx)
+	if len(events) != 1 {
+		t.Fatalf("Reply with failed notification expecting 1 failed event, got %d instead", len(events))
+	}
+
+	event := events[0]
+	if event.ID == "" || event.Key != "failed_key" || event.Vendor != "datadog" || event.Attempts != 1 || event.Error != err.Error() {
+		t.Errorf("Failed event is not matched with the notification, got %+v instead", event)
+	}
+	if string(event.Payload) != `{"key":"failed_key"}` {
+		t.Errorf("Failed event expecting the vendor payload, got %s instead", event.Payload)
+	}
+
+	// Failure on storage must be saved as well
+	err = uc.ReplyInThread(ctx, &Parameter{get: getErrorKey})
+	if err == nil {
+		t.Errorf("Reply with failed storage expecting error, got nil instead")
+	}
+	events, _ = uc.ListFailedEvents(ctx)
+	if len(events) != 2 {
+		t.Errorf("Reply with failed storage expecting 2 failed events, got %d instead", len(events))
+	}
+
+	// Raw webhook request is saved instead of the notification when it is kept
+	source := entity.Source{Webhook: "generic/ci", Query: "channel=failed_channel", Payload: []byte(`{"raw":true}`)}
+	uc.ReplyInThread(ctx, &sourceParameter{payloadParameter{cycleParameter{key: "source_key"}, "failed_channel"}, source})
+	events, _ = uc.ListFailedEvents(ctx)
+	if len(events) != 3 {
+		t.Errorf("Reply with raw webhook request expecting 3 failed events, got %d instead", len(events))
+	}
+	for _, event := range events {
+		if event.Key != "source_key" {
+			continue
+		}
+		if event.Webhook != source.Webhook || event.Query != source.Query || string(event.Payload) != string(source.Payload) {
+			t.Errorf("Failed event expecting the raw webhook request %+v, got %+v instead", source, event)
+		}
+	}
+}
+
+func TestReplayFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{})
+
+	event := entity.FailedEvent{ID: "event_1", Key: "replay_key", Attempts: 1}
+	storage.StoreFailedEvent(ctx, event)
+
+	// Replay which is still failing must keep the event with updated error
+	failed := New(storage, &notificationMock{}, Options{})
+	err := failed.ReplayFailedEvent(ctx, event, &payloadParameter{cycleParameter: cycleParameter{key: "replay_key"}, channel: "failed_channel"})
+	if err == nil {
+		t.Errorf("Replay with failed notification expecting error, got nil instead")
+	}
+	stored, _ := uc.GetFailedEvent(ctx, "event_1")
+	if stored.Attempts != 2 || stored.Error != err.Error() {
+		t.Errorf("Replay with failed notification expecting updated event, got %+v instead", stored)
+	}
+	if events, _ := uc.ListFailedEvents(ctx); len(events) != 1 {
+		t.Errorf("Replay with failed notification expecting no new failed event, got %d instead", len(events))
+	}
+
+	if len(stored.Channels) != 1 || stored.Channels[0] != "failed_channel" {
+		t.Errorf("Replay with failed notification expecting failed channel to be kept, got %+v instead", stored)
+	}
+
+	err = uc.ReplayFailedEvent(ctx, stored, &payloadParameter{cycleParameter: cycleParameter{key: "replay_key", status: entity.StatusTriggered}, channel: "failed_channel"})
+	if err != nil {
+		t.Errorf("Replay is not expecting error %s", err)
+	}
+	if stored, _ := uc.GetFailedEvent(ctx, "event_1"); stored.ID != "" {
+		t.Errorf("Replayed event expecting to be removed, got %+v instead", stored)
+	}
+	if incident, _ := storage.GetIncident(ctx, "replay_key"); len(incident.Threads) == 0 {
+		t.Errorf("Replayed event expecting to create the thread, got %+v instead", incident)
+	}
+
+	storage.StoreFailedEvent(ctx, entity.FailedEvent{ID: "event_2"})
+	err = uc.DiscardFailedEvent(ctx, "event_2")
+	if err != nil {
+		t.Errorf("Discard is not expecting error %s", err)
+	}
+	if events, _ := uc.ListFailedEvents(ctx); len(events) != 0 {
+		t.Errorf("Discarded event expecting to be removed, got %d events instead", len(events))
+	}
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrIncidentNotFound is returned when the incident is not stored or already removed
+var ErrIncidentNotFound = fmt.Errorf("Incident is not found")
+
+// AcknowledgeIncident will record the user as owner of the incident and show it in the main thread
+func (u *Usecase) AcknowledgeIncident(ctx context.Context, key, user string) error {
+	return u.changeIncident(ctx, key, func(incident *entity.Incident, now time.Time) string {
+		incident.AcknowledgedBy = user
+		incident.AcknowledgedAt = now
+		return fmt.Sprintf("Acknowledged by %s", user)
+	})
+}
+
+// ResolveIncident will mark the incident as recovered by the user so it is closed after the grace period
+func (u *Usecase) ResolveIncident(ctx context.Context, key, user string) error {
+	return u.changeIncident(ctx, key, func(incident *entity.Incident, now time.Time) string {
+		incident.Status = entity.StatusRecovered
+		incident.ResolvedBy = user
+		if incident.ResolvedAt.IsZero() {
+			incident.ResolvedAt = now
+		}
+		return fmt.Sprintf("Resolved by %s", user)
+	})
+}
+
+// CloseIncident will mark the incident as recovered by the user and close it immediately without waiting for the grace period
+func (u *Usecase) CloseIncident(ctx context.Context, key, user string) error {
+	return u.changeIncident(ctx, key, func(incident *entity.Incident, now time.Time) string {
+		incident.Status = entity.StatusRecovered
+		incident.ResolvedBy = user
+		if incident.ResolvedAt.IsZero() {
+			incident.ResolvedAt = now
+		}
+		incident.ClosedAt = now
+		return fmt.Sprintf("Closed by %s", user)
+	})
+}
+
+// SnoozeIncident will suppress replies of the incident for the given duration. Suppressed notifications are still counted in the main thread
+func (u *Usecase) SnoozeIncident(ctx context.Context, key, user string, duration time.Duration) error {
+	return u.changeIncident(ctx, key, func(incident *entity.Incident, now time.Time) string {
+		incident.SnoozedUntil = now.Add(duration)
+		return fmt.Sprintf("Snoozed by %s until %s", user, entity.FormatTime(incident.SnoozedUntil))
+	})
+}
+
+// changeIncident will apply the change into the stored incident, rewrite its main threads and reply the returned note in its threads
+func (u *Usecase) changeIncident(ctx context.Context, key string, change func(incident *entity.Incident, now time.Time) string) error {
+	unlock, err := u.lockIncident(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	incident, err := u.storage.GetIncident(ctx, key)
+	if err != nil {
+		log.Errorf("Failed to get incident from storage because %s", err)
+		return err
+	}
+	if len(incident.Threads) == 0 {
+		return ErrIncidentNotFound
+	}
+
+	now := time.Now()
+	note := change(&incident, now)
+	incident.LastUpdate = now
+
+	err = u.storage.RegisterIncident(ctx, key, incident)
+	if err != nil {
+		log.Errorf("Failed to register incident into storage because %s", err)
+		return fmt.Errorf("Failed to register incident into storage because %s", err)
+	}
+
+	// Failure in one thread does not stop the note delivered to the others
+	result := newDelivery()
+	for _, thread := range incident.Threads {
+		err = u.updateParent(ctx, key, thread, incident, "")
+		if err != nil {
+			log.Error(err)
+			result.done(thread.Channel, err)
+			continue
+		}
+
+		_, err = u.notification.SendMessage(ctx, entity.Notification{
+			Key:     key,
+			Channel: thread.Channel,
+			Title:   threadTitle(incident, thread),
+			Message: note,
+			Color:   incident.Status.Color,
+			Metadata: map[string]string{
+				"timestamp": thread.ThreadID,
+			},
+		})
+		if err != nil {
+			log.Errorf("Failed to send message because %s", err)
+			err = fmt.Errorf("Failed to send message because %s", err)
+		}
+		result.done(thread.Channel, err)
+	}
+
+	return result.err()
+}
+
+// isSnoozed will check whether the incident is snoozed so the notification is not replied. Recovery and notification resolved to a channel which is not notified yet are always replied
+func (u *Usecase) isSnoozed(incident entity.Incident, channels []string, param entity.ReplyInThread, now time.Time) bool {
+	if param.GetStatus().IsRecovered() || !now.Before(incident.SnoozedUntil) {
+		return false
+	}
+
+	for _, channel := range channels {
+		if _, ok := incident.GetThread(channel); !ok {
+			return false
+		}
+	}
+
+	return true
+}
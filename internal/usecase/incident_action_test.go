@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+func TestIncidentActions(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{})
+
+	err := uc.AcknowledgeIncident(ctx, "action_key", "<@U1>")
+	if err != ErrIncidentNotFound {
+		t.Errorf("Acknowledge unknown incident expecting error %s, got %v instead", ErrIncidentNotFound, err)
+	}
+
+	uc.ReplyInThread(ctx, &cycleParameter{key: "action_key", status: entity.StatusTriggered})
+
+	err = uc.AcknowledgeIncident(ctx, "action_key", "<@U1>")
+	if err != nil {
+		t.Fatalf("Acknowledge is not expecting error %s", err)
+	}
+	incident, _ := storage.GetIncident(ctx, "action_key")
+	if incident.AcknowledgedBy != "<@U1>" || incident.AcknowledgedAt.IsZero() {
+		t.Errorf("Acknowledge expecting owner to be recorded, got %+v instead", incident)
+	}
+	parent := notif.updated[len(notif.updated)-1]
+	if !strings.Contains(parent.Message, "Acknowledged By: *<@U1>*") || parent.Metadata["timestamp"] != "thread_1" || parent.Key != "action_key" {
+		t.Errorf("Acknowledge expecting main thread to show the owner, got %+v instead", parent)
+	}
+	if reply := notif.sent[len(notif.sent)-1]; reply.Message != "Acknowledged by <@U1>" || reply.Metadata["timestamp"] != "thread_1" {
+		t.Errorf("Acknowledge expecting note in the thread, got %+v instead", reply)
+	}
+
+	// Snoozed incident only count the notification in the main thread
+	err = uc.SnoozeIncident(ctx, "action_key", "<@U2>", time.Hour)
+	if err != nil {
+		t.Fatalf("Snooze is not expecting error %s", err)
+	}
+	sent := len(notif.sent)
+	uc.ReplyInThread(ctx, &cycleParameter{key: "action_key", status: entity.StatusWarning})
+	if len(notif.sent) != sent {
+		t.Errorf("Snoozed incident expecting no reply, got %d new messages instead", len(notif.sent)-sent)
+	}
+	if parent := notif.updated[len(notif.updated)-1]; !strings.Contains(parent.Message, "Snoozed Until") || !strings.Contains(parent.Message, "×1") {
+		t.Errorf("Snoozed incident expecting main thread to show snooze and counter, got %s instead", parent.Message)
+	}
+
+	err = uc.ResolveIncident(ctx, "action_key", "<@U3>")
+	if err != nil {
+		t.Fatalf("Resolve is not expecting error %s", err)
+	}
+	incident, _ = storage.GetIncident(ctx, "action_key")
+	if incident.Status != entity.StatusRecovered || incident.ResolvedAt.IsZero() || incident.ResolvedBy != "<@U3>" {
+		t.Errorf("Resolve expecting incident to be recovered, got %+v instead", incident)
+	}
+	if parent := notif.updated[len(notif.updated)-1]; parent.Color != entity.StatusRecovered.Color || !strings.Contains(parent.Message, "Resolved By: *<@U3>*") {
+		t.Errorf("Resolve expecting main thread to be recovered, got %+v instead", parent)
+	}
+
+	// Recovery is replied even when the incident is snoozed
+	sent = len(notif.sent)
+	uc.ReplyInThread(ctx, &cycleParameter{key: "action_key", status: entity.StatusRecovered})
+	if len(notif.sent) != sent+1 {
+		t.Errorf("Recovery of snoozed incident expecting a reply, got %d new messages instead", len(notif.sent)-sent)
+	}
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ListOpenIncidents will return every incident which is not closed yet mapped by its key. Archive of expired incident is not listed
+func (u *Usecase) ListOpenIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	incidents, err := u.storage.ListIncidents(ctx)
+	if err != nil {
+		log.Errorf("Failed to list incidents from storage because %s", err)
+		return nil, fmt.Errorf("Failed to list incidents from storage because %s", err)
+	}
+
+	now := time.Now()
+	for key, incident := range incidents {
+		state := u.state(incident, now)
+		if state == stateClosed || state == stateExpired || len(incident.Threads) == 0 {
+			delete(incidents, key)
+		}
+	}
+
+	return incidents, nil
+}
+
+// GetIncident will return the stored incident of the key. ErrIncidentNotFound is returned when it is not stored
+func (u *Usecase) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
+	incident, err := u.storage.GetIncident(ctx, key)
+	if err != nil {
+		log.Errorf("Failed to get incident from storage because %s", err)
+		return entity.Incident{}, err
+	}
+	if len(incident.Threads) == 0 {
+		return entity.Incident{}, ErrIncidentNotFound
+	}
+
+	return incident, nil
+}
+
+// MuteIncidents will suppress notifications of every incident which key is matched with the pattern for the given duration.
+// Muted incident which is not notified yet is dropped, otherwise it is only counted in the main thread
+func (u *Usecase) MuteIncidents(ctx context.Context, pattern, user string, duration time.Duration) (entity.Mute, error) {
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return entity.Mute{}, fmt.Errorf("Invalid pattern %s because %s", pattern, err)
+	}
+	if duration <= 0 {
+		return entity.Mute{}, fmt.Errorf("Invalid duration %s, it must be positive", duration)
+	}
+
+	now := time.Now()
+	mute := entity.Mute{
+		ID:        newEventID(),
+		Pattern:   pattern,
+		CreatedBy: user,
+		CreatedAt: now,
+		Until:     now.Add(duration),
+	}
+
+	err = u.storage.StoreMute(ctx, mute)
+	if err != nil {
+		log.Errorf("Failed to store mute into storage because %s", err)
+		return entity.Mute{}, fmt.Errorf("Failed to store mute into storage because %s", err)
+	}
+
+	return mute, nil
+}
+
+// ListMutes will return every mute which is still active
+func (u *Usecase) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	mutes, err := u.storage.ListMutes(ctx)
+	if err != nil {
+		log.Errorf("Failed to list mutes from storage because %s", err)
+		return nil, fmt.Errorf("Failed to list mutes from storage because %s", err)
+	}
+
+	now := time.Now()
+	active := []entity.Mute{}
+	for _, mute := range mutes {
+		if mute.IsActive(now) {
+			active = append(active, mute)
+		}
+	}
+
+	return active, nil
+}
+
+// isMuted will check whether the incident key is matched with any active mute. Incident is not muted when the mutes can not be listed
+func (u *Usecase) isMuted(ctx context.Context, key string, now time.Time) bool {
+	mutes, err := u.storage.ListMutes(ctx)
+	if err != nil {
+		log.Warnf("Failed to list mutes from storage because %s", err)
+		return false
+	}
+
+	for _, mute := range mutes {
+		if mute.IsActive(now) && mute.Match(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expireMutes will remove every mute which is no longer active
+func (u *Usecase) expireMutes(ctx context.Context) {
+	mutes, err := u.storage.ListMutes(ctx)
+	if err != nil {
+		log.Errorf("Failed to list mutes from storage because %s", err)
+		return
+	}
+
+	now := time.Now()
+	for _, mute := range mutes {
+		if mute.IsActive(now) {
+			continue
+		}
+
+		err = u.storage.RemoveMute(ctx, mute.ID)
+		if err != nil {
+			log.Errorf("Failed to remove mute %s because %s", mute.ID, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+func TestListOpenIncidents(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	uc := New(storage, &recorderNotification{}, Options{GracePeriod: time.Minute, ReopenWindow: time.Hour})
+
+	uc.ReplyInThread(ctx, &cycleParameter{key: "open_key", status: entity.StatusTriggered})
+	uc.ReplyInThread(ctx, &cycleParameter{key: "closed_key", status: entity.StatusTriggered})
+	uc.ReplyInThread(ctx, &cycleParameter{key: "resolved_key", status: entity.StatusRecovered})
+
+	err := uc.CloseIncident(ctx, "closed_key", "<@U1>")
+	if err != nil {
+		t.Fatalf("Close is not expecting error %s", err)
+	}
+
+	incidents, err := uc.ListOpenIncidents(ctx)
+	if err != nil {
+		t.Fatalf("List open incidents is not expecting error %s", err)
+	}
+	if _, ok := incidents["closed_key"]; ok || len(incidents) != 2 {
+		t.Errorf("List open incidents expecting open_key and resolved_key, got %v instead", incidents)
+	}
+
+	incident, err := uc.GetIncident(ctx, "closed_key")
+	if err != nil || incident.ResolvedBy != "<@U1>" || uc.state(incident, time.Now()) != stateClosed {
+		t.Errorf("Closed incident expecting to be closed immediately, got %+v and error %v instead", incident, err)
+	}
+	if _, err = uc.GetIncident(ctx, "unknown_key"); err != ErrIncidentNotFound {
+		t.Errorf("Get unknown incident expecting error %s, got %v instead", ErrIncidentNotFound, err)
+	}
+
+	// Closed incident triggered again inside the reopen window continue its thread
+	uc.ReplyInThread(ctx, &cycleParameter{key: "closed_key", status: entity.StatusTriggered})
+	incident, _ = uc.GetIncident(ctx, "closed_key")
+	if !incident.ClosedAt.IsZero() || len(incident.Threads) != 1 || uc.state(incident, time.Now()) != stateOpen {
+		t.Errorf("Reopened incident expecting to be open in its thread, got %+v instead", incident)
+	}
+}
+
+func TestMuteIncidents(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{})
+
+	_, err := uc.MuteIncidents(ctx, "db-[", "<@U1>", time.Hour)
+	if err == nil {
+		t.Errorf("Mute with invalid pattern expecting error")
+	}
+	_, err = uc.MuteIncidents(ctx, "db-*", "<@U1>", 0)
+	if err == nil {
+		t.Errorf("Mute with zero duration expecting error")
+	}
+
+	uc.ReplyInThread(ctx, &cycleParameter{key: "db-primary", status: entity.StatusTriggered})
+	mute, err := uc.MuteIncidents(ctx, "DB-*", "<@U1>", time.Hour)
+	if err != nil {
+		t.Fatalf("Mute is not expecting error %s", err)
+	}
+	storage.StoreMute(ctx, entity.Mute{ID: "expired", Pattern: "*", Until: time.Now().Add(-time.Minute)})
+
+	mutes, _ := uc.ListMutes(ctx)
+	if len(mutes) != 1 || mutes[0].ID != mute.ID || mutes[0].CreatedBy != "<@U1>" {
+		t.Errorf("List mutes expecting only the active mute, got %+v instead", mutes)
+	}
+
+	// New muted incident is dropped and existing one is only counted in the main thread
+	sent := len(notif.sent)
+	uc.ReplyInThread(ctx, &cycleParameter{key: "db-replica", status: entity.StatusTriggered})
+	uc.ReplyInThread(ctx, &cycleParameter{key: "db-primary", status: entity.StatusWarning})
+	if len(notif.sent) != sent {
+		t.Errorf("Muted incidents expecting no message, got %d new messages instead", len(notif.sent)-sent)
+	}
+	if incident, _ := storage.GetIncident(ctx, "db-primary"); incident.Repeats != 1 {
+		t.Errorf("Muted incident expecting to be counted, got %d repeats instead", incident.Repeats)
+	}
+	if _, err = uc.GetIncident(ctx, "db-replica"); err != ErrIncidentNotFound {
+		t.Errorf("Muted new incident expecting not to be stored, got %v instead", err)
+	}
+
+	// Recovery of muted incident is still replied
+	uc.ReplyInThread(ctx, &cycleParameter{key: "db-primary", status: entity.StatusRecovered})
+	if len(notif.sent) != sent+1 {
+		t.Errorf("Recovery of muted incident expecting a reply, got %d new messages instead", len(notif.sent)-sent)
+	}
+
+	uc.ExpireIncidents(ctx)
+	if _, ok := storage.mutes["expired"]; ok {
+		t.Errorf("Expired mute expecting to be removed by ExpireIncidents")
+	}
+}
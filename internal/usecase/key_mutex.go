@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// keyMutex is collection of mutex identified by key so process of different keys can run in parallel
+type keyMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock is mutex of single key with the number of goroutine holding or waiting for it
+type keyLock struct {
+	sync.Mutex
+	count int
+}
+
+func newKeyMutex() *keyMutex {
+	return &keyMutex{
+		locks: map[string]*keyLock{},
+	}
+}
+
+// Lock will wait until no other goroutine hold the key and lock it. Returned function must be called to release the key
+func (k *keyMutex) Lock(key string) func() {
+	k.mutex.Lock()
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		k.locks[key] = lock
+	}
+	lock.count++
+	k.mutex.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		k.mutex.Lock()
+		lock.count--
+		if lock.count == 0 {
+			delete(k.locks, key)
+		}
+		k.mutex.Unlock()
+	}
+}
+
+// lockIncident will lock the incident inside this process and, when the storage support it, across every replica sharing the storage.
+// Returned function must be called to release the incident
+func (u *Usecase) lockIncident(ctx context.Context, key string) (func(), error) {
+	unlock := u.keys.Lock(key)
+
+	// Prevent other replica sharing the storage from processing the same incident
+	if locker, ok := u.storage.(Locker); ok {
+		unlockStorage, err := locker.Lock(ctx, key)
+		if err != nil {
+			unlock()
+			log.Errorf("Failed to lock incident %s because %s", key, err)
+			return nil, err
+		}
+
+		return func() {
+			unlockStorage()
+			unlock()
+		}, nil
+	}
+
+	return unlock, nil
+}
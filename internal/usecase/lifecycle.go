@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// incidentState is lifecycle state of a stored incident
+type incidentState int
+
+const (
+	// stateOpen is incident which is not recovered yet
+	stateOpen incidentState = iota
+
+	// stateResolved is recovered incident still inside the grace period
+	stateResolved
+
+	// stateClosed is recovered incident after the grace period and still inside the reopen window
+	stateClosed
+
+	// stateExpired is closed incident after the reopen window. Its key is free to be used by a new incident
+	stateExpired
+)
+
+// state will return lifecycle state of the incident at the given time
+func (u *Usecase) state(incident entity.Incident, now time.Time) incidentState {
+	if incident.ResolvedAt.IsZero() {
+		return stateOpen
+	}
+
+	closedAt := incident.ClosedAt
+	if closedAt.IsZero() {
+		if u.gracePeriod <= 0 {
+			return stateResolved
+		}
+		closedAt = incident.ResolvedAt.Add(u.gracePeriod)
+	}
+	if now.Before(closedAt) {
+		return stateResolved
+	}
+	if now.Before(closedAt.Add(u.reopenWindow)) {
+		return stateClosed
+	}
+
+	return stateExpired
+}
+
+// previousThreadLinks will return links of the previous threads the incident is reopened from
+func (u *Usecase) previousThreadLinks(ctx context.Context, incident entity.Incident) string {
+	return strings.Join(u.ThreadLinks(ctx, incident.PreviousThreads), "\n")
+}
+
+// ThreadLinks will return link of every thread. Thread channel and id are shown instead when the notification channel can not return the link
+func (u *Usecase) ThreadLinks(ctx context.Context, threads []entity.Thread) []string {
+	links := []string{}
+	for _, thread := range threads {
+		link := fmt.Sprintf("thread %s in %s", thread.ThreadID, thread.Channel)
+		if permalinker, ok := u.notification.(Permalinker); ok {
+			permalink, err := permalinker.GetPermalink(ctx, thread)
+			if err != nil {
+				log.Warnf("Failed to get permalink of thread %s because %s", thread.ThreadID, err)
+			} else {
+				link = permalink
+			}
+		}
+		links = append(links, link)
+	}
+
+	return links
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+type permalinkNotification struct {
+	recorderNotification
+}
+
+func (n *permalinkNotification) GetPermalink(ctx context.Context, thread entity.Thread) (string, error) {
+	return "http://chat/" + thread.Channel + "/" + thread.ThreadID, nil
+}
+
+func TestState(t *testing.T) {
+	uc := New(newMemoryStorage(), &recorderNotification{}, Options{GracePeriod: time.Minute, ReopenWindow: time.Hour})
+	disabled := New(newMemoryStorage(), &recorderNotification{}, Options{})
+	now := time.Now()
+
+	incidents := []entity.Incident{
+		{},
+		{ResolvedAt: now.Add(-30 * time.Second)},
+		{ResolvedAt: now.Add(-30 * time.Minute)},
+		{ResolvedAt: now.Add(-2 * time.Hour)},
+	}
+	expected := []incidentState{stateOpen, stateResolved, stateClosed, stateExpired}
+	expectedDisabled := []incidentState{stateOpen, stateResolved, stateResolved, stateResolved}
+
+	for k, incident := range incidents {
+		if state := uc.state(incident, now); state != expected[k] {
+			t.Errorf("Incident %d expecting state %d, got %d instead", k, expected[k], state)
+		}
+		if state := disabled.state(incident, now); state != expectedDisabled[k] {
+			t.Errorf("Incident %d without grace period expecting state %d, got %d instead", k, expectedDisabled[k], state)
+		}
+	}
+}
+
+func TestReplyInThreadLifecycle(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &permalinkNotification{}
+	uc := New(storage, notif, Options{GracePeriod: time.Minute, ReopenWindow: time.Hour})
+
+	// resolvedAgo moves the resolved time of the stored incident to the past
+	resolvedAgo := func(duration time.Duration) {
+		incident, _ := storage.GetIncident(ctx, "lifecycle_key")
+		incident.ResolvedAt = time.Now().Add(-duration)
+		storage.RegisterIncident(ctx, "lifecycle_key", incident)
+	}
+	reply := func(status entity.IncidentStatus) entity.Incident {
+		err := uc.ReplyInThread(ctx, &cycleParameter{key: "lifecycle_key", status: status})
+		if err != nil {
+			t.Fatalf("Reply with status %s is not expecting error %s", status.Message, err)
+		}
+		incident, _ := storage.GetIncident(ctx, "lifecycle_key")
+		return incident
+	}
+
+	reply(entity.StatusTriggered)
+	incident := reply(entity.StatusRecovered)
+	if incident.ResolvedAt.IsZero() {
+		t.Errorf("Recovered incident expecting resolved time, got %+v instead", incident)
+	}
+
+	// Re-trigger inside the reopen window continue the same thread
+	resolvedAgo(30 * time.Minute)
+	incident = reply(entity.StatusTriggered)
+	if !incident.ResolvedAt.IsZero() || incident.Threads[0].ThreadID != "thread_1" || len(notif.parents()) != 1 {
+		t.Errorf("Reopened incident expecting to continue thread_1, got %+v instead", incident)
+	}
+
+	// Re-trigger after the reopen window start a new thread linking to the previous one
+	reply(entity.StatusRecovered)
+	resolvedAgo(2 * time.Hour)
+	incident = reply(entity.StatusTriggered)
+	if len(incident.Threads) != 1 || incident.Threads[0].ThreadID != "thread_2" {
+		t.Errorf("Incident triggered after reopen window expecting new thread_2, got %+v instead", incident.Threads)
+	}
+	if len(incident.PreviousThreads) != 1 || incident.PreviousThreads[0].ThreadID != "thread_1" {
+		t.Errorf("Incident triggered after reopen window expecting previous thread_1, got %+v instead", incident.PreviousThreads)
+	}
+	parents := notif.parents()
+	if len(parents) != 2 || !strings.Contains(parents[1].Message, "http://chat/channel/thread_1") {
+		t.Errorf("New main thread expecting link to previous thread, got %+v instead", parents)
+	}
+
+	// Recovery of expired incident only archive it
+	reply(entity.StatusRecovered)
+	resolvedAgo(2 * time.Hour)
+	sent := len(notif.sent)
+	incident = reply(entity.StatusRecovered)
+	if len(incident.Threads) != 0 || len(notif.sent) != sent {
+		t.Errorf("Recovery of expired incident expecting to be archived without message, got %+v instead", incident)
+	}
+}
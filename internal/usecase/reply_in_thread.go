@@ -10,78 +10,240 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// ReplyInThread will check whether the incident is already notified, create a new thread for new incident and reply in the thread for existing incident
+// ReplyInThread will check whether the incident is already notified, create a new thread for new incident and reply in the thread for existing incident.
+// Notification failed to be processed is saved as failed event so it can be replayed later
 func (u *Usecase) ReplyInThread(ctx context.Context, param entity.ReplyInThread) error {
+	err := u.replyInThread(ctx, param, nil)
+	if err != nil {
+		u.storeFailedEvent(ctx, param, err)
+	}
+
+	return err
+}
+
+// replyInThread will process the notification. Replayed failed event is never suppressed as duplicate because its reply was not delivered
+// even though the incident already records its detail, and it is only delivered into its failed channels so the others do not get it twice
+func (u *Usecase) replyInThread(ctx context.Context, param entity.ReplyInThread, replay *entity.FailedEvent) error {
+	// Image is resolved once before holding the incident lock as the vendor may wait for it to be rendered
+	image := param.GetImage()
+
+	// Process notification of the same incident one by one so only the first one create the thread
+	unlock, err := u.lockIncident(ctx, param.GetKey())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	incident, err := u.storage.GetIncident(ctx, param.GetKey())
 	if err != nil {
 		log.Errorf("Failed to get incident from storage because %s", err)
 		return err
 	}
 
-	threadID := incident.ThreadID
-	if threadID == "" {
-		// Sending Main Thread
-		threadID, err = u.sendMessage(ctx, "", param)
-		if err != nil {
-			log.Error(err)
-			return err
+	now := time.Now()
+	switch u.state(incident, now) {
+	case stateClosed:
+		log.Infof("Reopening closed incident %s in its previous threads", param.GetKey())
+	case stateExpired:
+		if param.GetStatus().IsRecovered() {
+			// Nothing to report for recovery of incident which is already closed, so only archive it
+			log.Infof("Archiving closed incident %s", param.GetKey())
+			err = u.storage.RemoveIncident(ctx, param.GetKey())
+			if err != nil {
+				log.Errorf("Failed to remove incident from storage because %s", err)
+				return fmt.Errorf("Failed to remove incident from storage because %s", err)
+			}
+			return nil
 		}
 
+		// Triggered again after the reopen window so new threads are created linking to the previous ones
+		incident = entity.Incident{PreviousThreads: incident.Threads}
+	}
+
+	channels := u.router.Route(param)
+	if len(channels) == 0 {
+		log.Errorf("No channel is resolved for incident %s", param.GetKey())
+		return fmt.Errorf("No channel is resolved for incident %s", param.GetKey())
+	}
+
+	// Notification of muted incident is dropped when it is not notified yet, otherwise it is suppressed like snoozed incident
+	muted := u.isMuted(ctx, param.GetKey(), now)
+	if muted && len(incident.Threads) == 0 {
+		log.Infof("Dropping notification of muted incident %s", param.GetKey())
+		return nil
+	}
+
+	// Identical re-notification and notification of snoozed incident are only counted in the main thread instead of replied
+	suppressed := (replay == nil && u.isDuplicate(incident, channels, param)) || u.isSnoozed(incident, channels, param, now) || (muted && !param.GetStatus().IsRecovered())
+
+	if len(incident.Threads) == 0 {
 		incident = entity.Incident{
-			Title:      param.GetTitle(),
-			ThreadID:   threadID,
-			Vendor:     param.GetVendor(),
-			Status:     param.GetStatus(),
-			LastUpdate: time.Now(),
+			Title:           param.GetTitle(),
+			Vendor:          param.GetVendor(),
+			PreviousThreads: incident.PreviousThreads,
 		}
+	} else if suppressed {
+		incident.Repeats++
 	} else {
-		// Update Main Thread
-		err = u.updateMessage(ctx, threadID, incident, param)
+		incident.Repeats = 0
+	}
+
+	// Conference link is generated once so it is kept for the whole incident
+	if incident.Bridge.Link == "" {
+		incident.Bridge = u.bridgeLink(ctx, param.GetKey(), channels)
+	}
+
+	// Only update the latest state so title and threads of existing incident are kept
+	incident.Status = param.GetStatus()
+	incident.LastUpdate = now
+	if !param.GetStatus().IsRecovered() {
+		incident.ResolvedAt = time.Time{}
+		incident.ResolvedBy = ""
+		incident.ClosedAt = time.Time{}
+	} else if incident.ResolvedAt.IsZero() {
+		incident.ResolvedAt = now
+	}
+	if !suppressed {
+		incident.DetailHash = detailHash(param)
+		incident.LastReply = now
+	}
+
+	// Render texts of every channel from the templates. Title of existing thread is kept like the incident title,
+	// while summary of the incident is rendered without channel template
+	data := newTemplateData(param, image, incident)
+	incident.Summary = u.templates.render(data, "").Summary
+	data.Incident = incident
+	contents := map[string]content{}
+	for _, channel := range channels {
+		contents[channel] = u.templates.render(data, channel)
+	}
+	for k, thread := range incident.Threads {
+		if _, ok := contents[thread.Channel]; !ok {
+			contents[thread.Channel] = u.templates.render(data, thread.Channel)
+		}
+		incident.Threads[k].Summary = contents[thread.Channel].Summary
+	}
+
+	// Update Main Thread of every channel already notified. Failure in one channel does not stop delivery to the others
+	result := newDelivery()
+	for _, thread := range incident.Threads {
+		if !replayed(replay, thread.Channel) {
+			continue
+		}
+
+		err = u.updateParent(ctx, param.GetKey(), thread, incident, image)
 		if err != nil {
 			log.Error(err)
-			return err
+		}
+		result.done(thread.Channel, err)
+	}
+
+	// Sending Main Thread to every newly resolved channel
+	for _, channel := range channels {
+		if _, ok := incident.GetThread(channel); ok || !replayed(replay, channel) {
+			continue
 		}
 
-		incident = entity.Incident{
-			Status:     param.GetStatus(),
-			LastUpdate: time.Now(),
+		thread := entity.Thread{Channel: channel, Title: contents[channel].Title, Summary: contents[channel].Summary}
+		thread.ThreadID, err = u.sendParent(ctx, param.GetKey(), thread, incident)
+		result.done(channel, err)
+		if err != nil {
+			log.Error(err)
+			continue
 		}
+		incident.Threads = append(incident.Threads, thread)
+	}
+	if len(incident.Threads) == 0 {
+		return result.err()
 	}
 
-	// Register Incident to Storage
+	// Register Incident to Storage. Incident is registered even when some main thread failed to be sent so the created threads are not duplicated on retry
 	err = u.storage.RegisterIncident(ctx, param.GetKey(), incident)
 	if err != nil {
 		log.Errorf("Failed to register incident into storage because %s", err)
 		return fmt.Errorf("Failed to register incident into storage because %s", err)
 	}
+	if suppressed {
+		log.Debugf("Suppressed repeated notification %d of incident %s", incident.Repeats, param.GetKey())
+		return result.err()
+	}
 
 	// Sending Thread
-	_, err = u.sendMessage(ctx, threadID, param)
+	for _, thread := range incident.Threads {
+		if !replayed(replay, thread.Channel) {
+			continue
+		}
+
+		err = u.sendReply(ctx, thread, param, contents[thread.Channel], image)
+		if err != nil {
+			log.Error(err)
+		}
+		result.done(thread.Channel, err)
+	}
+
+	err = result.err()
 	if err != nil {
-		log.Error(err)
-		return err
+		log.Warnf("Incident %s is partially notified. %s", param.GetKey(), err)
 	}
 
-	return nil
+	return err
+}
+
+// replayed will check whether the channel is delivered by the notification. Replay of partially delivered event is only delivered into its failed channels
+func replayed(replay *entity.FailedEvent, channel string) bool {
+	if replay == nil || len(replay.Channels) == 0 {
+		return true
+	}
+
+	for _, failed := range replay.Channels {
+		if failed == channel {
+			return true
+		}
+	}
+
+	return false
 }
 
-func (u *Usecase) sendMessage(ctx context.Context, threadID string, param entity.ReplyInThread) (string, error) {
-	message := param.GetSummary()
-	image := ""
-	if threadID != "" {
-		message = param.GetDetail()
-		image = param.GetImage()
+// parentMessage will return main thread message containing the latest summary of the thread and state of the incident.
+// Conference link is part of the summary so the summary template decides whether and where it is shown
+func (u *Usecase) parentMessage(incident entity.Incident, thread entity.Thread) string {
+	message := thread.Summary
+	if message == "" {
+		message = incident.Summary
+	}
+	if incident.Repeats > 0 {
+		message = fmt.Sprintf("%s\nStill %s: *×%d*", message, incident.Status.Message, incident.Repeats)
+	}
+	if incident.AcknowledgedBy != "" {
+		message = fmt.Sprintf("%s\nAcknowledged By: *%s* at %s", message, incident.AcknowledgedBy, entity.FormatTime(incident.AcknowledgedAt))
+	}
+	if incident.ResolvedBy != "" {
+		message = fmt.Sprintf("%s\nResolved By: *%s* at %s", message, incident.ResolvedBy, entity.FormatTime(incident.ResolvedAt))
+	}
+	if time.Now().Before(incident.SnoozedUntil) {
+		message = fmt.Sprintf("%s\nSnoozed Until: %s", message, entity.FormatTime(incident.SnoozedUntil))
+	}
+
+	return message
+}
+
+// sendParent will send main message of the incident into the channel and return its thread id
+func (u *Usecase) sendParent(ctx context.Context, key string, thread entity.Thread, incident entity.Incident) (string, error) {
+	message := u.parentMessage(incident, thread)
+	if len(incident.PreviousThreads) > 0 {
+		message = fmt.Sprintf("%s\nReopened From:\n%s", message, u.previousThreadLinks(ctx, incident))
 	}
 
 	threadID, err := u.notification.SendMessage(ctx, entity.Notification{
-		Channel: param.GetChannel(),
-		Title:   param.GetTitle(),
+		Key:     key,
+		Channel: thread.Channel,
+		Title:   threadTitle(incident, thread),
 		Message: message,
-		Color:   param.GetStatus().Color,
-		Image:   image,
+		Color:   incident.Status.Color,
 		Metadata: map[string]string{
-			"timestamp": threadID,
+			"timestamp": "",
 		},
+		Resolved: incident.Status.IsRecovered(),
 	})
 	if err != nil {
 		return "", fmt.Errorf("Failed to send message because %s", err)
@@ -90,15 +252,17 @@ func (u *Usecase) sendMessage(ctx context.Context, threadID string, param entity
 	return threadID, nil
 }
 
-func (u *Usecase) updateMessage(ctx context.Context, threadID string, incident entity.Incident, param entity.ReplyInThread) error {
-	err := u.notification.UpdateMessage(ctx, entity.Notification{
-		Channel: param.GetChannel(),
-		Title:   incident.Title,
-		Message: param.GetSummary(),
+// sendReply will send detail of the notification rendered for the channel into the thread
+func (u *Usecase) sendReply(ctx context.Context, thread entity.Thread, param entity.ReplyInThread, text content, image string) error {
+	_, err := u.notification.SendMessage(ctx, entity.Notification{
+		Key:     param.GetKey(),
+		Channel: thread.Channel,
+		Title:   text.Title,
+		Message: text.Detail,
 		Color:   param.GetStatus().Color,
-		Image:   param.GetImage(),
+		Image:   image,
 		Metadata: map[string]string{
-			"timestamp": threadID,
+			"timestamp": thread.ThreadID,
 		},
 	})
 	if err != nil {
@@ -107,3 +271,39 @@ func (u *Usecase) updateMessage(ctx context.Context, threadID string, incident e
 
 	return nil
 }
+
+// updateParent will rewrite main message of the thread with the latest state of the incident
+func (u *Usecase) updateParent(ctx context.Context, key string, thread entity.Thread, incident entity.Incident, image string) error {
+	err := u.notification.UpdateMessage(ctx, entity.Notification{
+		Key:     key,
+		Channel: thread.Channel,
+		Title:   threadTitle(incident, thread),
+		Message: u.parentMessage(incident, thread),
+		Color:   incident.Status.Color,
+		Image:   image,
+		Metadata: map[string]string{
+			"timestamp": thread.ThreadID,
+		},
+		Resolved: incident.Status.IsRecovered(),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to send message because %s", err)
+	}
+
+	return nil
+}
+
+// bridgeLink will return conference link of the incident. Incident is still notified without the link when it is failed to be generated
+func (u *Usecase) bridgeLink(ctx context.Context, key string, channels []string) entity.Bridge {
+	if u.bridge == nil {
+		return entity.Bridge{}
+	}
+
+	bridge, err := u.bridge.Link(ctx, key, channels)
+	if err != nil {
+		log.Warnf("Failed to generate conference link of incident %s because %s", key, err)
+		return entity.Bridge{}
+	}
+
+	return bridge
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,11 +17,30 @@ const (
 	getErrorKey   = "mock_error"
 	getEmptyKey   = "mock_empty"
 	getSuccessKey = "mock_success"
+	getStaleKey   = "mock_stale"
+	getRoutedKey  = "mock_routed"
 )
 
+// Thread of the mocked incident is in the channel of the notification, so it is updated in the same channel like before routing
 var successIncident = entity.Incident{
 	Title:      "Success Mock",
-	ThreadID:   "success_channel",
+	Threads:    []entity.Thread{{Channel: "success_channel", ThreadID: "success_channel"}},
+	Vendor:     "datadog",
+	Status:     entity.StatusWarning,
+	LastUpdate: time.Now(),
+}
+
+var updateIncident = entity.Incident{
+	Title:      "Update Mock",
+	Threads:    []entity.Thread{{Channel: "success_channel_update_success", ThreadID: "success_channel"}},
+	Vendor:     "datadog",
+	Status:     entity.StatusWarning,
+	LastUpdate: time.Now(),
+}
+
+var staleIncident = entity.Incident{
+	Title:      "Stale Mock",
+	Threads:    []entity.Thread{{Channel: "success_channel", ThreadID: "success_channel"}},
 	Vendor:     "datadog",
 	Status:     entity.StatusWarning,
 	LastUpdate: time.Now(),
@@ -29,8 +49,12 @@ var successIncident = entity.Incident{
 type storageMock struct{}
 
 func (s *storageMock) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
-	if strings.HasPrefix(key, "mock_success") {
+	if strings.HasPrefix(key, "mock_success_update") || strings.HasPrefix(key, "mock_routed") {
+		return updateIncident, nil
+	} else if strings.HasPrefix(key, "mock_success") {
 		return successIncident, nil
+	} else if strings.HasPrefix(key, "mock_stale") {
+		return staleIncident, nil
 	} else if strings.HasPrefix(key, "mock_empty") {
 		return entity.Incident{}, nil
 	}
@@ -45,6 +69,30 @@ func (s *storageMock) RegisterIncident(ctx context.Context, key string, incident
 func (s *storageMock) RemoveIncident(ctx context.Context, key string) error {
 	return nil
 }
+func (s *storageMock) ListIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	return nil, errorDefault
+}
+func (s *storageMock) StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error {
+	return nil
+}
+func (s *storageMock) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	return entity.FailedEvent{}, nil
+}
+func (s *storageMock) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	return nil, nil
+}
+func (s *storageMock) RemoveFailedEvent(ctx context.Context, id string) error {
+	return nil
+}
+func (s *storageMock) StoreMute(ctx context.Context, mute entity.Mute) error {
+	return nil
+}
+func (s *storageMock) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	return nil, nil
+}
+func (s *storageMock) RemoveMute(ctx context.Context, id string) error {
+	return nil
+}
 
 type notificationMock struct{}
 
@@ -76,6 +124,9 @@ func (p *Parameter) GetVendor() string {
 }
 func (p *Parameter) GetKey() string {
 	str := p.get
+	if p.update {
+		str += "_update"
+	}
 	if p.register {
 		str += "_register_success"
 	}
@@ -96,6 +147,9 @@ func (p *Parameter) GetStatus() entity.IncidentStatus {
 func (p *Parameter) GetImage() string {
 	return "http://image.com"
 }
+func (p *Parameter) GetTags() []string {
+	return []string{"service:mock"}
+}
 func (p *Parameter) GetChannel() string {
 	str := "failed_channel"
 	if p.send {
@@ -109,7 +163,7 @@ func (p *Parameter) GetChannel() string {
 
 func TestReplyInThread(t *testing.T) {
 	ctx := context.Background()
-	uc := New(&storageMock{}, &notificationMock{})
+	uc := New(&storageMock{}, &notificationMock{}, Options{})
 
 	usecase := []Parameter{
 		Parameter{get: getErrorKey, register: false, send: false},
@@ -122,6 +176,8 @@ func TestReplyInThread(t *testing.T) {
 		Parameter{get: getSuccessKey, register: false, send: true},
 		Parameter{get: getSuccessKey, register: true, send: true, update: false},
 		Parameter{get: getSuccessKey, register: true, send: true, update: true},
+		Parameter{get: getRoutedKey, register: true, send: true, update: false},
+		Parameter{get: getStaleKey, register: true, send: true, update: true},
 	}
 
 	expected := []error{
@@ -135,6 +191,8 @@ func TestReplyInThread(t *testing.T) {
 		errorDefault,
 		errorDefault,
 		nil,
+		nil,
+		errorDefault,
 	}
 
 	for k, v := range usecase {
@@ -148,3 +206,355 @@ func TestReplyInThread(t *testing.T) {
 		}
 	}
 }
+
+type memoryStorage struct {
+	mutex     sync.Mutex
+	incidents map[string]entity.Incident
+	failed    map[string]entity.FailedEvent
+	mutes     map[string]entity.Mute
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{incidents: map[string]entity.Incident{}, failed: map[string]entity.FailedEvent{}, mutes: map[string]entity.Mute{}}
+}
+
+func (s *memoryStorage) GetIncident(ctx context.Context, key string) (entity.Incident, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.incidents[key], nil
+}
+func (s *memoryStorage) RegisterIncident(ctx context.Context, key string, incident entity.Incident) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.incidents[key] = incident
+	return nil
+}
+func (s *memoryStorage) RemoveIncident(ctx context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.incidents, key)
+	return nil
+}
+func (s *memoryStorage) ListIncidents(ctx context.Context) (map[string]entity.Incident, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	incidents := map[string]entity.Incident{}
+	for key, incident := range s.incidents {
+		incidents[key] = incident
+	}
+	return incidents, nil
+}
+func (s *memoryStorage) StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failed[event.ID] = event
+	return nil
+}
+func (s *memoryStorage) GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.failed[id], nil
+}
+func (s *memoryStorage) ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	events := []entity.FailedEvent{}
+	for _, event := range s.failed {
+		events = append(events, event)
+	}
+	return events, nil
+}
+func (s *memoryStorage) RemoveFailedEvent(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.failed, id)
+	return nil
+}
+func (s *memoryStorage) StoreMute(ctx context.Context, mute entity.Mute) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.mutes[mute.ID] = mute
+	return nil
+}
+func (s *memoryStorage) ListMutes(ctx context.Context) ([]entity.Mute, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	mutes := []entity.Mute{}
+	for _, mute := range s.mutes {
+		mutes = append(mutes, mute)
+	}
+	return mutes, nil
+}
+func (s *memoryStorage) RemoveMute(ctx context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.mutes, id)
+	return nil
+}
+
+type recorderNotification struct {
+	mutex   sync.Mutex
+	delay   time.Duration
+	counter int
+	sent    []entity.Notification
+	updated []entity.Notification
+}
+
+func (n *recorderNotification) SendMessage(ctx context.Context, param entity.Notification) (string, error) {
+	time.Sleep(n.delay)
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.sent = append(n.sent, param)
+	if threadID := param.Metadata["timestamp"]; threadID != "" {
+		return threadID, nil
+	}
+	n.counter++
+	return fmt.Sprintf("thread_%d", n.counter), nil
+}
+func (n *recorderNotification) UpdateMessage(ctx context.Context, param entity.Notification) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.updated = append(n.updated, param)
+	return nil
+}
+func (n *recorderNotification) parents() []entity.Notification {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	result := []entity.Notification{}
+	for _, message := range n.sent {
+		if message.Metadata["timestamp"] == "" {
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+type cycleParameter struct {
+	Parameter
+	key    string
+	status entity.IncidentStatus
+}
+
+func (p *cycleParameter) GetKey() string {
+	return p.key
+}
+func (p *cycleParameter) GetStatus() entity.IncidentStatus {
+	return p.status
+}
+func (p *cycleParameter) GetChannel() string {
+	return "channel"
+}
+
+func TestReplyInThreadCycle(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{})
+
+	cycle := []entity.IncidentStatus{
+		entity.StatusTriggered,
+		entity.StatusWarning,
+		entity.StatusTriggered,
+		entity.StatusRecovered,
+	}
+
+	for k, status := range cycle {
+		err := uc.ReplyInThread(ctx, &cycleParameter{key: "cycle_key", status: status})
+		if err != nil {
+			t.Fatalf("Reply %d with status %s is not expecting error %s", k, status.Message, err)
+		}
+
+		incident, _ := storage.GetIncident(ctx, "cycle_key")
+		if len(incident.Threads) != 1 || incident.Threads[0].ThreadID != "thread_1" || incident.Threads[0].Channel != "channel" {
+			t.Errorf("Reply %d with status %s expecting thread %s in channel, got %+v instead", k, status.Message, "thread_1", incident.Threads)
+		}
+		if incident.Title != "title" || incident.Vendor != "datadog" {
+			t.Errorf("Reply %d with status %s lost incident title or vendor, got %+v instead", k, status.Message, incident)
+		}
+		if incident.Status != status {
+			t.Errorf("Reply %d expecting status %s, got %s instead", k, status.Message, incident.Status.Message)
+		}
+	}
+
+	if len(notif.parents()) != 1 {
+		t.Errorf("Cycle expecting 1 parent message, got %d instead", len(notif.parents()))
+	}
+	if len(notif.sent) != len(cycle)+1 {
+		t.Errorf("Cycle expecting %d messages, got %d instead", len(cycle)+1, len(notif.sent))
+	}
+	if len(notif.updated) != len(cycle)-1 {
+		t.Errorf("Cycle expecting %d parent updates, got %d instead", len(cycle)-1, len(notif.updated))
+	}
+	for k, message := range notif.updated {
+		if message.Metadata["timestamp"] != "thread_1" {
+			t.Errorf("Parent update expecting thread %s, got %s instead", "thread_1", message.Metadata["timestamp"])
+		}
+		// Only the recovered incident is no longer acted on from its main thread
+		if resolved := k == len(notif.updated)-1; message.Resolved != resolved {
+			t.Errorf("Parent update %d expecting resolved %t, got %t instead", k, resolved, message.Resolved)
+		}
+	}
+	if last := notif.updated[len(notif.updated)-1]; last.Color != entity.StatusRecovered.Color {
+		t.Errorf("Last parent update expecting color %s, got %s instead", entity.StatusRecovered.Color, last.Color)
+	}
+}
+
+type lockerStorage struct {
+	*memoryStorage
+	err      error
+	locked   []string
+	unlocked []string
+}
+
+func (s *lockerStorage) Lock(ctx context.Context, key string) (func(), error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.locked = append(s.locked, key)
+	return func() {
+		s.unlocked = append(s.unlocked, key)
+	}, nil
+}
+
+func TestReplyInThreadLocker(t *testing.T) {
+	ctx := context.Background()
+	storage := &lockerStorage{memoryStorage: newMemoryStorage()}
+	notif := &recorderNotification{}
+	uc := New(storage, notif, Options{})
+
+	err := uc.ReplyInThread(ctx, &cycleParameter{key: "locked_key", status: entity.StatusTriggered})
+	if err != nil {
+		t.Errorf("Reply with locker storage is not expecting error %s", err)
+	}
+	if len(storage.locked) != 1 || len(storage.unlocked) != 1 || storage.locked[0] != "locked_key" {
+		t.Errorf("Reply expecting locked_key to be locked and unlocked once, got %v and %v instead", storage.locked, storage.unlocked)
+	}
+
+	storage.err = errorDefault
+	err = uc.ReplyInThread(ctx, &cycleParameter{key: "locked_key", status: entity.StatusTriggered})
+	if err != errorDefault {
+		t.Errorf("Reply with failed lock expecting error %s, got %s instead", errorDefault, err)
+	}
+	if len(notif.sent) != 2 {
+		t.Errorf("Reply with failed lock should not send any message, got %d messages instead", len(notif.sent))
+	}
+}
+
+func TestReplyInThreadConcurrent(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{delay: time.Millisecond}
+	uc := New(storage, notif, Options{})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := "burst_key"
+			if i%5 == 0 {
+				key = fmt.Sprintf("other_key_%d", i)
+			}
+			err := uc.ReplyInThread(ctx, &cycleParameter{key: key, status: entity.StatusTriggered})
+			if err != nil {
+				t.Errorf("Concurrent reply %d is not expecting error %s", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(notif.parents()) != 11 {
+		t.Errorf("Concurrent reply expecting 11 parent messages, got %d instead", len(notif.parents()))
+	}
+
+	incident, _ := storage.GetIncident(ctx, "burst_key")
+	replies := 0
+	for _, message := range notif.sent {
+		if message.Metadata["timestamp"] == incident.Threads[0].ThreadID {
+			replies++
+		}
+	}
+	if replies != 40 {
+		t.Errorf("Concurrent reply expecting 40 replies in thread %s, got %d instead", incident.Threads[0].ThreadID, replies)
+	}
+	if len(uc.keys.locks) != 0 {
+		t.Errorf("Concurrent reply expecting every key lock to be released, got %d instead", len(uc.keys.locks))
+	}
+}
+
+type bridgeMock struct {
+	counter int
+}
+
+func (b *bridgeMock) Link(ctx context.Context, key string, channels []string) (entity.Bridge, error) {
+	b.counter++
+	if key == "broken_bridge_key" {
+		return entity.Bridge{}, errorDefault
+	}
+	return entity.Bridge{Name: "Hangout", Link: fmt.Sprintf("https://meet/%s-%d", key, b.counter)}, nil
+}
+
+func TestReplyInThreadBridge(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	bridge := &bridgeMock{}
+	uc := New(storage, notif, Options{Bridge: bridge})
+
+	steps := []*cycleParameter{
+		{key: "bridge_key", status: entity.StatusTriggered},
+		{key: "bridge_key", status: entity.StatusRecovered},
+	}
+	for k, param := range steps {
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Bridge reply %d is not expecting error %s", k, err)
+		}
+	}
+
+	// Link is generated once and kept for the whole incident
+	incident, _ := storage.GetIncident(ctx, "bridge_key")
+	expected := entity.Bridge{Name: "Hangout", Link: "https://meet/bridge_key-1"}
+	if incident.Bridge != expected {
+		t.Errorf("Bridge expecting %+v stored in incident, got %+v instead", expected, incident.Bridge)
+	}
+	if !strings.HasPrefix(notif.parents()[0].Message, "*Hangout Link* : https://meet/bridge_key-1\n\nFrom: *datadog*") {
+		t.Errorf("Bridge expecting link in parent message, got %s instead", notif.parents()[0].Message)
+	}
+	if !strings.HasPrefix(notif.updated[0].Message, "*Hangout Link* : https://meet/bridge_key-1") {
+		t.Errorf("Bridge expecting link in updated parent message, got %s instead", notif.updated[0].Message)
+	}
+
+	// Summary template decides whether the link is shown
+	templates, _ := NewTemplates(TemplatesConfig{
+		Channels: map[string]TemplateConfig{
+			"custom_channel": {Summary: "{{.Status.Message}}"},
+			"bridge_channel": {Summary: "{{.Status.Message}} on {{.Incident.Bridge.Link}}"},
+		},
+	})
+	custom := New(storage, notif, Options{Bridge: bridge, Templates: templates})
+	for _, channel := range []string{"custom_channel", "bridge_channel"} {
+		err := custom.ReplyInThread(ctx, &payloadParameter{cycleParameter{key: channel + "_key", status: entity.StatusTriggered}, channel})
+		if err != nil {
+			t.Fatalf("Bridge template reply is not expecting error %s", err)
+		}
+
+		incident, _ := storage.GetIncident(ctx, channel+"_key")
+		expectedMessage := "Triggered"
+		if channel == "bridge_channel" {
+			expectedMessage = "Triggered on " + incident.Bridge.Link
+		}
+		if message := notif.parents()[len(notif.parents())-1].Message; incident.Bridge.Link == "" || message != expectedMessage {
+			t.Errorf("Bridge template of %s expecting %q, got %q instead", channel, expectedMessage, message)
+		}
+	}
+
+	// Incident is still notified without link when the link is failed to be generated
+	err := uc.ReplyInThread(ctx, &cycleParameter{key: "broken_bridge_key", status: entity.StatusTriggered})
+	if err != nil {
+		t.Fatalf("Broken bridge is not expecting error %s", err)
+	}
+	if message := notif.parents()[len(notif.parents())-1].Message; strings.Contains(message, "Link") || !strings.HasPrefix(message, "From: ") {
+		t.Errorf("Broken bridge expecting no link in parent message, got %s instead", message)
+	}
+}
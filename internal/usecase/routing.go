@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+// RoutingRule defines destination channels of incident matched with every configured condition. Condition which is not configured match every incident
+type RoutingRule struct {
+	// Vendor is matched with the vendor name case-insensitively
+	Vendor string `json:"vendor"`
+
+	// Tags must all be owned by the incident. Each tag is glob pattern such as "service:payment-*"
+	Tags []string `json:"tags"`
+
+	// Status is list of status code or message, the incident status must be one of them
+	Status []string `json:"status"`
+
+	// Title is regular expression matched with the incident title
+	Title string `json:"title"`
+
+	// Channels is list of channel the matched incident is sent to. Each channel get its own thread
+	Channels []string `json:"channels"`
+
+	// Continue will keep evaluating the next rules after this rule is matched so the incident can be sent to channels of several rules
+	Continue bool `json:"continue"`
+}
+
+// RoutingConfig defines how the destination channels of an incident are resolved
+type RoutingConfig struct {
+	Rules          []RoutingRule `json:"rules"`
+	DefaultChannel string        `json:"default_channel"`
+}
+
+// route is compiled RoutingRule
+type route struct {
+	RoutingRule
+	statuses []entity.IncidentStatus
+	title    *regexp.Regexp
+}
+
+// Router resolves destination channels of an incident based on the routing rules
+type Router struct {
+	routes         []route
+	defaultChannel string
+}
+
+// NewRouter will compile every routing rule and return the router used by usecase
+func NewRouter(config RoutingConfig) (*Router, error) {
+	routes := []route{}
+	for k, rule := range config.Rules {
+		if len(rule.Channels) == 0 {
+			return nil, fmt.Errorf("Channels of routing rule %d is required", k)
+		}
+
+		for _, tag := range rule.Tags {
+			if _, err := path.Match(tag, ""); err != nil {
+				return nil, fmt.Errorf("Failed to parse tag %s of routing rule %d because %s", tag, k, err)
+			}
+		}
+
+		statuses := []entity.IncidentStatus{}
+		for _, value := range rule.Status {
+			status, ok := entity.ParseStatus(value)
+			if !ok {
+				return nil, fmt.Errorf("Unknown status %s of routing rule %d", value, k)
+			}
+			statuses = append(statuses, status)
+		}
+
+		var title *regexp.Regexp
+		if rule.Title != "" {
+			var err error
+			title, err = regexp.Compile(rule.Title)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse title of routing rule %d because %s", k, err)
+			}
+		}
+
+		routes = append(routes, route{
+			RoutingRule: rule,
+			statuses:    statuses,
+			title:       title,
+		})
+	}
+
+	return &Router{
+		routes:         routes,
+		defaultChannel: config.DefaultChannel,
+	}, nil
+}
+
+// Route will return every channel the incident should be sent to. Channels of matched rules take precedence over the channel sent in the payload,
+// and the default channel is used when none of them is available
+func (r *Router) Route(param entity.ReplyInThread) []string {
+	channels := []string{}
+	for _, route := range r.routes {
+		if !route.match(param) {
+			continue
+		}
+
+		channels = appendChannels(channels, route.Channels...)
+		if !route.Continue {
+			break
+		}
+	}
+
+	if len(channels) == 0 {
+		channels = appendChannels(channels, param.GetChannel())
+	}
+	if len(channels) == 0 {
+		channels = appendChannels(channels, r.defaultChannel)
+	}
+
+	return channels
+}
+
+// match will check whether the incident match with every condition of the rule
+func (r route) match(param entity.ReplyInThread) bool {
+	if r.Vendor != "" && !strings.EqualFold(r.Vendor, param.GetVendor()) {
+		return false
+	}
+
+	if len(r.statuses) > 0 {
+		matched := false
+		for _, status := range r.statuses {
+			if status == param.GetStatus() {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if r.title != nil && !r.title.MatchString(param.GetTitle()) {
+		return false
+	}
+
+	tags := param.GetTags()
+	for _, pattern := range r.Tags {
+		matched := false
+		for _, tag := range tags {
+			if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(tag)); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// appendChannels will append every non empty channel which is not inside the list yet
+func appendChannels(list []string, channels ...string) []string {
+	for _, channel := range channels {
+		if channel == "" {
+			continue
+		}
+
+		exist := false
+		for _, item := range list {
+			if item == channel {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			list = append(list, channel)
+		}
+	}
+
+	return list
+}
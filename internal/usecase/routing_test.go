@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+type routeParameter struct {
+	Parameter
+	vendor  string
+	title   string
+	status  entity.IncidentStatus
+	tags    []string
+	channel string
+}
+
+func (p *routeParameter) GetKey() string {
+	return "route_key"
+}
+func (p *routeParameter) GetVendor() string {
+	return p.vendor
+}
+func (p *routeParameter) GetTitle() string {
+	return p.title
+}
+func (p *routeParameter) GetStatus() entity.IncidentStatus {
+	return p.status
+}
+func (p *routeParameter) GetTags() []string {
+	return p.tags
+}
+func (p *routeParameter) GetChannel() string {
+	return p.channel
+}
+
+func TestNewRouter(t *testing.T) {
+	configs := []RoutingConfig{
+		{},
+		{Rules: []RoutingRule{{Vendor: "Datadog", Tags: []string{"team:*"}, Status: []string{"error"}, Title: "^CPU", Channels: []string{"C1"}}}},
+		{Rules: []RoutingRule{{Vendor: "Datadog"}}},
+		{Rules: []RoutingRule{{Tags: []string{"team:["}, Channels: []string{"C1"}}}},
+		{Rules: []RoutingRule{{Status: []string{"broken"}, Channels: []string{"C1"}}}},
+		{Rules: []RoutingRule{{Title: "(", Channels: []string{"C1"}}}},
+	}
+	expected := []bool{true, true, false, false, false, false}
+
+	for k, config := range configs {
+		_, err := NewRouter(config)
+		if (err == nil) != expected[k] {
+			t.Errorf("Routing config %d expecting valid %t, got error %v instead", k, expected[k], err)
+		}
+	}
+}
+
+func TestRoute(t *testing.T) {
+	router, err := NewRouter(RoutingConfig{
+		DefaultChannel: "C_DEFAULT",
+		Rules: []RoutingRule{
+			{Tags: []string{"team:payment", "env:prod*"}, Channels: []string{"C_PAYMENT"}, Continue: true},
+			{Status: []string{"Triggered"}, Title: "(?i)database", Channels: []string{"C_DBA", "C_PAYMENT"}},
+			{Vendor: "grafana", Channels: []string{"C_GRAFANA"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Routing config is not expecting error %s", err)
+	}
+
+	params := []*routeParameter{
+		{vendor: "Datadog", tags: []string{"team:payment", "env:production"}, status: entity.StatusWarning},
+		{vendor: "Datadog", tags: []string{"team:payment", "env:production"}, status: entity.StatusTriggered, title: "Database latency"},
+		{vendor: "Datadog", tags: []string{"team:payment"}, status: entity.StatusTriggered, title: "Database latency"},
+		{vendor: "Grafana", tags: []string{"team:payment"}, status: entity.StatusRecovered, title: "Database latency"},
+		{vendor: "Datadog", tags: []string{"team:core"}, channel: "C_PAYLOAD"},
+		{vendor: "Datadog"},
+	}
+	expected := [][]string{
+		{"C_PAYMENT"},
+		{"C_PAYMENT", "C_DBA"},
+		{"C_DBA", "C_PAYMENT"},
+		{"C_GRAFANA"},
+		{"C_PAYLOAD"},
+		{"C_DEFAULT"},
+	}
+
+	for k, param := range params {
+		channels := router.Route(param)
+		if !reflect.DeepEqual(channels, expected[k]) {
+			t.Errorf("Route %d expecting channels %v, got %v instead", k, expected[k], channels)
+		}
+	}
+}
+
+func TestReplyInThreadFanOut(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	router, _ := NewRouter(RoutingConfig{
+		Rules: []RoutingRule{
+			{Tags: []string{"team:payment"}, Channels: []string{"C_PAYMENT", "C_OPS"}},
+			{Status: []string{"error"}, Channels: []string{"C_PAGER"}},
+		},
+	})
+	uc := New(storage, notif, Options{Router: router})
+
+	steps := []*routeParameter{
+		{vendor: "Datadog", tags: []string{"team:payment"}, status: entity.StatusWarning},
+		{vendor: "Datadog", tags: []string{"team:core"}, status: entity.StatusTriggered},
+		{vendor: "Datadog", tags: []string{"team:payment"}, status: entity.StatusRecovered},
+	}
+	// Escalated incident keep its previous threads and get a new thread in the newly resolved channel
+	expected := [][]string{
+		{"C_PAYMENT", "C_OPS"},
+		{"C_PAYMENT", "C_OPS", "C_PAGER"},
+		{"C_PAYMENT", "C_OPS", "C_PAGER"},
+	}
+
+	for k, param := range steps {
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Fan out reply %d is not expecting error %s", k, err)
+		}
+
+		incident, _ := storage.GetIncident(ctx, "route_key")
+		channels := []string{}
+		for _, thread := range incident.Threads {
+			channels = append(channels, thread.Channel)
+		}
+		if !reflect.DeepEqual(channels, expected[k]) {
+			t.Errorf("Fan out reply %d expecting threads in %v, got %v instead", k, expected[k], channels)
+		}
+	}
+
+	if len(notif.parents()) != 3 {
+		t.Errorf("Fan out expecting 3 parent messages, got %d instead", len(notif.parents()))
+	}
+	if len(notif.updated) != 5 {
+		t.Errorf("Fan out expecting 5 parent updates, got %d instead", len(notif.updated))
+	}
+	for _, message := range notif.sent {
+		if message.Metadata["timestamp"] == "" {
+			continue
+		}
+
+		incident, _ := storage.GetIncident(ctx, "route_key")
+		thread, ok := incident.GetThread(message.Channel)
+		if !ok || thread.ThreadID != message.Metadata["timestamp"] {
+			t.Errorf("Reply in channel %s is sent to unknown thread %s", message.Channel, message.Metadata["timestamp"])
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// TemplateConfig contains text/template of the notification texts. Empty template is inherited from the less specific config
+type TemplateConfig struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Detail  string `json:"detail"`
+}
+
+// TemplatesConfig contains templates of every vendor and channel. Channel template takes precedence over vendor template,
+// and the built-in default template is used when neither is configured
+type TemplatesConfig struct {
+	Vendors  map[string]TemplateConfig `json:"vendors"`
+	Channels map[string]TemplateConfig `json:"channels"`
+}
+
+// TemplateData is the value available inside the templates
+type TemplateData struct {
+	Key     string
+	Vendor  string
+	Channel string
+	Status  entity.IncidentStatus
+	Tags    []string
+	Image   string
+
+	// Title, Summary and Detail are texts built by the vendor. Summary only contains facts specific to the vendor, e.g. number of firing alerts of Alertmanager
+	Title   string
+	Summary string
+	Detail  string
+
+	// Payload is the vendor notification so every field of its payload is accessible, e.g. {{.Payload.AlertID}} of Datadog
+	Payload entity.ReplyInThread
+
+	// Incident is the stored incident including the latest notification, e.g. {{.Incident.Bridge.Link}} is its conference link
+	Incident entity.Incident
+}
+
+// defaultTemplateConfig is the built-in templates used when neither vendor nor channel template is configured
+var defaultTemplateConfig = TemplateConfig{
+	Title:   "{{.Title}}",
+	Summary: "{{with .Incident.Bridge.Link}}*{{$.Incident.Bridge.Name}} Link* : {{.}}\n\n{{end}}From: *{{.Vendor}}*\nCurrent Status: *{{.Status.Message}}*{{with .Summary}}\n{{.}}{{end}}",
+	Detail:  "{{.Detail}}",
+}
+
+// content is the rendered texts of the notification for a channel
+type content struct {
+	Title   string
+	Summary string
+	Detail  string
+}
+
+// messageTemplates is parsed templates of a vendor or channel. Nil template is not configured
+type messageTemplates struct {
+	title   *template.Template
+	summary *template.Template
+	detail  *template.Template
+}
+
+// Templates renders the notification texts of each channel
+type Templates struct {
+	vendors  map[string]messageTemplates
+	channels map[string]messageTemplates
+	defaults messageTemplates
+}
+
+// templateFuncs is helper functions available inside the templates
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+	"time":  entity.FormatTime,
+}
+
+// NewTemplates will parse every template of the config
+func NewTemplates(config TemplatesConfig) (*Templates, error) {
+	var err error
+	templates := &Templates{
+		vendors:  map[string]messageTemplates{},
+		channels: map[string]messageTemplates{},
+	}
+	templates.defaults, err = parseTemplates("default", defaultTemplateConfig)
+	if err != nil {
+		return nil, err
+	}
+	for vendor, vendorConfig := range config.Vendors {
+		templates.vendors[strings.ToLower(vendor)], err = parseTemplates("vendor "+vendor, vendorConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for channel, channelConfig := range config.Channels {
+		templates.channels[channel], err = parseTemplates("channel "+channel, channelConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
+
+// parseTemplates will parse the configured templates of a vendor or channel
+func parseTemplates(name string, config TemplateConfig) (messageTemplates, error) {
+	result := messageTemplates{}
+	fields := []struct {
+		name  string
+		text  string
+		value **template.Template
+	}{
+		{"title", config.Title, &result.title},
+		{"summary", config.Summary, &result.summary},
+		{"detail", config.Detail, &result.detail},
+	}
+	for _, field := range fields {
+		if field.text == "" {
+			continue
+		}
+
+		parsed, err := template.New(fmt.Sprintf("%s %s", name, field.name)).Funcs(templateFuncs).Option("missingkey=zero").Parse(field.text)
+		if err != nil {
+			return messageTemplates{}, fmt.Errorf("Failed to parse %s template of %s because %s", field.name, name, err)
+		}
+		*field.value = parsed
+	}
+
+	return result, nil
+}
+
+// newTemplateData will return the template value of the notification. Image is resolved once by the caller as the vendor may wait for it to be rendered
+func newTemplateData(param entity.ReplyInThread, image string, incident entity.Incident) TemplateData {
+	return TemplateData{
+		Key:      param.GetKey(),
+		Vendor:   param.GetVendor(),
+		Status:   param.GetStatus(),
+		Tags:     param.GetTags(),
+		Image:    image,
+		Title:    param.GetTitle(),
+		Summary:  param.GetSummary(),
+		Detail:   param.GetDetail(),
+		Payload:  param,
+		Incident: incident,
+	}
+}
+
+// render will return the notification texts for the channel. Template failed to be executed falls back to the less specific one so the notification is not lost
+func (t *Templates) render(data TemplateData, channel string) content {
+	data.Channel = channel
+	vendor := t.vendors[strings.ToLower(data.Vendor)]
+	override := t.channels[channel]
+
+	return content{
+		Title:   execute(data, override.title, vendor.title, t.defaults.title),
+		Summary: execute(data, override.summary, vendor.summary, t.defaults.summary),
+		Detail:  execute(data, override.detail, vendor.detail, t.defaults.detail),
+	}
+}
+
+// execute will render the first configured template which is executed successfully. Empty text is returned when none is
+func execute(data TemplateData, templates ...*template.Template) string {
+	for _, tmpl := range templates {
+		if tmpl == nil {
+			continue
+		}
+
+		buffer := &bytes.Buffer{}
+		err := tmpl.Execute(buffer, data)
+		if err != nil {
+			log.Warnf("Failed to execute %s template of incident %s because %s", tmpl.Name(), data.Key, err)
+			continue
+		}
+
+		return buffer.String()
+	}
+
+	return ""
+}
+
+// threadTitle will return title rendered for the thread. Thread created before the templates use the incident title
+func threadTitle(incident entity.Incident, thread entity.Thread) string {
+	if thread.Title != "" {
+		return thread.Title
+	}
+	return incident.Title
+}
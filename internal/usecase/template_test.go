@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alvintzz/alert-thread/internal/entity"
+)
+
+func TestNewTemplates(t *testing.T) {
+	configs := []TemplatesConfig{
+		{},
+		{Vendors: map[string]TemplateConfig{"Datadog": {Title: "{{upper .Title}}", Detail: "{{.Detail}} {{join .Tags \",\"}}"}}},
+		{Channels: map[string]TemplateConfig{"C1": {Summary: "{{.Summary"}}},
+		{Vendors: map[string]TemplateConfig{"Datadog": {Title: "{{unknown .Title}}"}}},
+	}
+	expected := []bool{true, true, false, false}
+
+	for k, config := range configs {
+		_, err := NewTemplates(config)
+		if (err == nil) != expected[k] {
+			t.Errorf("Templates config %d expecting valid %t, got error %v instead", k, expected[k], err)
+		}
+	}
+}
+
+type summaryParameter struct {
+	routeParameter
+}
+
+func (p *summaryParameter) GetSummary() string {
+	return ""
+}
+
+func TestRenderTemplates(t *testing.T) {
+	templates, err := NewTemplates(TemplatesConfig{
+		Vendors: map[string]TemplateConfig{
+			"Datadog": {Title: "[{{upper .Vendor}}] {{.Title}}", Detail: "{{.Detail}} on {{join .Tags \", \"}}"},
+		},
+		Channels: map[string]TemplateConfig{
+			"C_OPS":    {Title: "{{.Incident.Title}} in {{.Channel}}", Summary: "{{lower .Status.Message}}: {{.Summary}}"},
+			"C_BROKEN": {Detail: "{{.Payload.Missing}}"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Templates config is not expecting error %s", err)
+	}
+
+	incident := entity.Incident{Title: "First title"}
+	channels := []string{"C_DEFAULT", "C_OPS", "C_BROKEN"}
+	expected := []content{
+		{Title: "[DATADOG] title", Summary: "From: *datadog*\nCurrent Status: *Warning*\nsummary", Detail: "detail on service:mock"},
+		{Title: "First title in C_OPS", Summary: "warning: summary", Detail: "detail on service:mock"},
+		{Title: "[DATADOG] title", Summary: "From: *datadog*\nCurrent Status: *Warning*\nsummary", Detail: "detail on service:mock"},
+	}
+
+	data := newTemplateData(&Parameter{get: "template_key"}, "http://image.com", incident)
+	for k, channel := range channels {
+		result := templates.render(data, channel)
+		if result != expected[k] {
+			t.Errorf("Render templates of %s expecting %+v, got %+v instead", channel, expected[k], result)
+		}
+	}
+
+	// Vendor without template uses the built-in default templates
+	param := &routeParameter{vendor: "Grafana", title: "Grafana title", status: entity.StatusTriggered}
+	expectedDefault := content{Title: "Grafana title", Summary: "From: *Grafana*\nCurrent Status: *Triggered*\nsummary", Detail: "detail"}
+	if result := templates.render(newTemplateData(param, "", incident), "C_DEFAULT"); result != expectedDefault {
+		t.Errorf("Render templates without config expecting %+v, got %+v instead", expectedDefault, result)
+	}
+
+	// Default summary reproduces the summary of Datadog before the templates
+	baseline := "*Hangout Link* : http://g.co/meet/tkpd-route_key\n\nFrom: *Datadog*\nCurrent Status: *Triggered*"
+	bridged := entity.Incident{Bridge: entity.Bridge{Name: "Hangout", Link: "http://g.co/meet/tkpd-route_key"}}
+	if result := templates.render(newTemplateData(&summaryParameter{routeParameter{vendor: "Datadog", status: entity.StatusTriggered}}, "", bridged), "C_DEFAULT"); result.Summary != baseline {
+		t.Errorf("Render default summary expecting %q, got %q instead", baseline, result.Summary)
+	}
+}
+
+func TestReplyInThreadTemplates(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	notif := &recorderNotification{}
+	templates, _ := NewTemplates(TemplatesConfig{
+		Channels: map[string]TemplateConfig{
+			"channel": {Title: "{{.Key}}: {{.Title}}", Summary: "Custom {{.Summary}}", Detail: "Custom {{.Detail}}"},
+		},
+	})
+	uc := New(storage, notif, Options{Templates: templates})
+
+	steps := []*cycleParameter{
+		{key: "template_key", status: entity.StatusTriggered},
+		{key: "template_key", status: entity.StatusRecovered},
+	}
+	for k, param := range steps {
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Template reply %d is not expecting error %s", k, err)
+		}
+	}
+
+	parents := notif.parents()
+	if len(parents) != 1 || parents[0].Title != "template_key: title" || parents[0].Message != "Custom summary" {
+		t.Errorf("Template expecting rendered parent message, got %+v instead", parents)
+	}
+	for _, message := range notif.sent[1:] {
+		if message.Title != "template_key: title" || message.Message != "Custom detail" {
+			t.Errorf("Template expecting rendered reply, got %+v instead", message)
+		}
+	}
+	if len(notif.updated) != 1 || notif.updated[0].Title != "template_key: title" || notif.updated[0].Message != "Custom summary" {
+		t.Errorf("Template expecting rendered parent update, got %+v instead", notif.updated)
+	}
+
+	// Incident keeps the texts of the default templates while the thread keeps the rendered texts
+	incident, _ := storage.GetIncident(ctx, "template_key")
+	if incident.Title != "title" || incident.Summary != "From: *datadog*\nCurrent Status: *Recovered*\nsummary" || incident.Threads[0].Title != "template_key: title" {
+		t.Errorf("Template expecting rendered texts stored in the thread, got %+v instead", incident)
+	}
+}
+
+type imageParameter struct {
+	routeParameter
+	images int
+}
+
+func (p *imageParameter) GetImage() string {
+	p.images++
+	return "http://image.com"
+}
+
+func TestReplyInThreadImageOnce(t *testing.T) {
+	ctx := context.Background()
+	notif := &recorderNotification{}
+	router, _ := NewRouter(RoutingConfig{
+		Rules: []RoutingRule{{Vendor: "*", Channels: []string{"C1", "C2", "C3"}}},
+	})
+	uc := New(newMemoryStorage(), notif, Options{Router: router})
+
+	// Vendor may wait for the image to be rendered, so it is resolved once per notification instead of per channel
+	for k := 0; k < 2; k++ {
+		param := &imageParameter{routeParameter: routeParameter{vendor: "*", status: entity.StatusTriggered}}
+		err := uc.ReplyInThread(ctx, param)
+		if err != nil {
+			t.Fatalf("Image reply %d is not expecting error %s", k, err)
+		}
+		if param.images != 1 {
+			t.Errorf("Image reply %d expecting image to be resolved once, got %d times instead", k, param.images)
+		}
+	}
+	if last := notif.sent[len(notif.sent)-1]; last.Image != "http://image.com" {
+		t.Errorf("Image reply expecting image in the reply, got %+v instead", last)
+	}
+}
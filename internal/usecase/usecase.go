@@ -2,15 +2,31 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/alvintzz/alert-thread/internal/entity"
 )
 
-// Storage is interface of storage use to save incidents
+// Storage is interface of storage use to save incidents, notifications failed to be delivered and muted incidents
 type Storage interface {
 	GetIncident(ctx context.Context, key string) (entity.Incident, error)
 	RegisterIncident(ctx context.Context, key string, incident entity.Incident) error
 	RemoveIncident(ctx context.Context, key string) error
+	ListIncidents(ctx context.Context) (map[string]entity.Incident, error)
+
+	StoreFailedEvent(ctx context.Context, event entity.FailedEvent) error
+	GetFailedEvent(ctx context.Context, id string) (entity.FailedEvent, error)
+	ListFailedEvents(ctx context.Context) ([]entity.FailedEvent, error)
+	RemoveFailedEvent(ctx context.Context, id string) error
+
+	StoreMute(ctx context.Context, mute entity.Mute) error
+	ListMutes(ctx context.Context) ([]entity.Mute, error)
+	RemoveMute(ctx context.Context, id string) error
+}
+
+// Locker is optional interface of storage able to lock an incident so only one service replica process it at a time
+type Locker interface {
+	Lock(ctx context.Context, key string) (unlock func(), err error)
 }
 
 // Notification is interface of notification channel used to notify an incident
@@ -19,16 +35,81 @@ type Notification interface {
 	UpdateMessage(ctx context.Context, param entity.Notification) error
 }
 
+// Bridge is interface of conference bridge provider used to generate link where the incident is discussed
+type Bridge interface {
+	Link(ctx context.Context, key string, channels []string) (entity.Bridge, error)
+}
+
+// Options contains optional configuration of the usecase
+type Options struct {
+	// Router resolves destination channels of incident. Channel sent in the payload is used when it is not set
+	Router *Router
+
+	// DedupWindow is how long identical notification is suppressed after the last reply. Zero disables the deduplication
+	DedupWindow time.Duration
+
+	// GracePeriod is how long recovered incident wait before it is closed. Zero keeps recovered incident open forever
+	GracePeriod time.Duration
+
+	// ReopenWindow is how long closed incident continue its threads when triggered again. Afterward new threads are created linking to the previous ones
+	ReopenWindow time.Duration
+
+	// StaleTTL is how long incident without any update is kept before it is removed by ExpireIncidents. Zero keeps it forever
+	StaleTTL time.Duration
+
+	// AutoCloseReply will reply in the threads of stale incident before it is removed
+	AutoCloseReply bool
+
+	// Templates renders title, summary and detail of each channel. Built-in default templates are used when it is not set
+	Templates *Templates
+
+	// Bridge generates conference link of new incident. Conference link is not shown when it is not set
+	Bridge Bridge
+}
+
+// Permalinker is optional interface of notification channel able to return link of a thread
+type Permalinker interface {
+	GetPermalink(ctx context.Context, thread entity.Thread) (string, error)
+}
+
 // Usecase contains all dependencies for slack-alert flow
 type Usecase struct {
 	storage      Storage
 	notification Notification
+	router       *Router
+	dedupWindow  time.Duration
+	gracePeriod  time.Duration
+	reopenWindow time.Duration
+	staleTTL     time.Duration
+	autoClose    bool
+	templates    *Templates
+	bridge       Bridge
+	keys         *keyMutex
 }
 
 // New will return object contain the usecases served by this service
-func New(store Storage, notif Notification) *Usecase {
+func New(store Storage, notif Notification, options Options) *Usecase {
+	router := options.Router
+	if router == nil {
+		router = &Router{}
+	}
+
+	templates := options.Templates
+	if templates == nil {
+		templates, _ = NewTemplates(TemplatesConfig{})
+	}
+
 	return &Usecase{
 		storage:      store,
 		notification: notif,
+		router:       router,
+		dedupWindow:  options.DedupWindow,
+		gracePeriod:  options.GracePeriod,
+		reopenWindow: options.ReopenWindow,
+		staleTTL:     options.StaleTTL,
+		autoClose:    options.AutoCloseReply,
+		templates:    templates,
+		bridge:       options.Bridge,
+		keys:         newKeyMutex(),
 	}
 }